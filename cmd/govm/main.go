@@ -2,21 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
 	"github.com/sbonaiva/govm/internal/util"
 )
 
 const (
-	logCmd  = "log"
-	logFile = "govm.log"
-	goVersionsURL = "https://go.dev/dl/?mode=json&include=all"
-	goDownloadURL = "https://go.dev/dl/%s"
+	configCmd  = "config"
+	logCmd     = "log"
+	resolveCmd = "resolve"
+	logFile    = "govm.log"
+
+	httpTimeout   = 30 * time.Second
+	httpRetries   = 3
+	httpRetryWait = time.Second
 )
 
 var (
@@ -24,35 +37,119 @@ var (
 )
 
 func main() {
-	ctx := context.Background()
+	os.Exit(run())
+}
 
-	if !(len(os.Args) > 1 && os.Args[1] == logCmd) {
-		logFilePath := path.Join(os.TempDir(), logFile)
-		if err := os.Remove(logFilePath); err != nil && !os.IsNotExist(err) {
-			util.PrintError("Failed to remove log file")
-			os.Exit(1)
-		}
+// run runs govm and returns its exit code, so deferred calls run before main exits.
+func run() int {
+	// The first interrupt cancels ctx so the running command can roll back; once it
+	// is cancelled, stop restores the default behaviour and a second one terminates.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	shim := isShim(os.Args[0])
+
+	closeLog, err := openLog(os.Args)
+	if err != nil {
+		util.PrintError("Failed to create log file")
+		fmt.Println(err)
+		return domain.ExitFailure
+	}
+	defer closeLog()
+
+	osGateway := gateway.NewOsGateway()
+	httpConfig := &gateway.HttpConfig{
+		Timeout:   httpTimeout,
+		Retries:   httpRetries,
+		RetryWait: httpRetryWait,
+	}
 
-		logFile, err := os.Create(logFilePath)
-		if err != nil {
-			util.PrintError("Failed to create log file")
+	var config domain.Config
+	if homeDir, err := osGateway.GetUserHomeDir(); err == nil {
+		home := domain.Action{HomeDir: homeDir}
+		if config, err = osGateway.ReadConfig(home.ConfigFile()); err != nil {
+			util.PrintError("Failed to read config file %s", home.ConfigFile())
+			fmt.Println(err)
+			return domain.ExitFailure
+		}
+		// config is left to run so the invalid value can be fixed with "govm config set".
+		if err := config.Validate(); err != nil && !(len(os.Args) > 1 && os.Args[1] == configCmd) {
+			util.PrintError("Invalid config file %s", home.ConfigFile())
 			fmt.Println(err)
-			os.Exit(1)
+			return domain.ExitCode(err)
 		}
-		defer logFile.Close()
+		home.Root = config.Root
+		httpConfig.IndexCacheFile = home.CacheIndexFile()
+	}
+	config.ApplyEnv(os.Getenv)
+	httpConfig.GoVersionURL = config.ReleaseIndexURL()
+	httpConfig.GoDownloadURL = config.DownloadURL()
+	httpConfig.IndexTTL = config.IndexCacheTTL()
 
-		slog.SetDefault(slog.New(slog.NewJSONHandler(logFile, nil)))
+	switch config.ColorMode() {
+	case domain.AlwaysColor:
+		color.NoColor = false
+	case domain.NeverColor:
+		color.NoColor = true
 	}
 
-	httpGateway := gateway.NewHttpGateway(&gateway.HttpConfig{
-		GoVersionURL:  goVersionsURL,
-		GoDownloadURL: goDownloadURL,
-	})
-	osGateway := gateway.NewOsGateway()
-	rootCmd := api.NewRootCmd(ctx, Version, httpGateway, osGateway)
+	render := &util.RenderConfig{Format: domain.TextOutput}
+	rootCmd := api.NewRootCmd(ctx, Version, &config, httpConfig, render, osGateway)
+
+	if shim {
+		rootCmd.SetArgs(append([]string{api.ShimCmd, shimName(os.Args[0])}, os.Args[1:]...))
+	}
 
 	if err := rootCmd.Execute(); err != nil {
-		util.PrintError(err.Error())
-		os.Exit(1)
+		// The command run by exec or a shim already reported its own failure.
+		var exitErr *domain.CommandExitError
+		if !errors.As(err, &exitErr) {
+			util.NewRenderer(render).Error(err)
+		}
+		return domain.ExitCode(err)
 	}
+
+	return domain.ExitSuccess
+}
+
+// openLog sends the logs to a new govm.log in the temp directory, replacing the log
+// of the previous command. The shims and resolve, which the shell hook runs on every
+// directory change, discard their logs instead so the log of the command the user
+// ran is kept, and so does log, which prints it.
+func openLog(args []string) (func() error, error) {
+	cmd := ""
+	if len(args) > 1 {
+		cmd = args[1]
+	}
+
+	switch {
+	case isShim(args[0]) || cmd == resolveCmd:
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return func() error { return nil }, nil
+	case cmd == logCmd:
+		return func() error { return nil }, nil
+	}
+
+	logFilePath := path.Join(os.TempDir(), logFile)
+	if err := os.Remove(logFilePath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	file, err := os.Create(logFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(file, nil)))
+	return file.Close, nil
+}
+
+// shimName returns the tool name govm was invoked as, e.g. "go" for ~/.govm/shims/go.
+func shimName(arg0 string) string {
+	return strings.TrimSuffix(filepath.Base(arg0), ".exe")
+}
+
+func isShim(arg0 string) bool {
+	return slices.Contains(domain.Shims, shimName(arg0))
 }
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "Command", args: []string{"govm", "uninstall", "--yes"}, expected: ""},
+		{name: "Resolve", args: []string{"govm", "resolve", "--bin"}, expected: "previous"},
+		{name: "Log", args: []string{"govm", "log"}, expected: "previous"},
+		{name: "Shim", args: []string{"/root/.govm/shims/go", "version"}, expected: "previous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("TMPDIR", dir)
+			logFilePath := filepath.Join(dir, logFile)
+			assert.NoError(t, os.WriteFile(logFilePath, []byte("previous"), 0644))
+
+			closeLog, err := openLog(tt.args)
+			assert.NoError(t, err)
+			assert.NoError(t, closeLog())
+
+			content, err := os.ReadFile(logFilePath)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+}
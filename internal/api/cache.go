@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func NewCacheCmd(ctx context.Context, handler handler.CacheHandler) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:     "cache",
+		Short:   "Manage downloaded Go archives",
+		Long:    "Manage the verified Go archives kept in ~/.govm/cache, which are reused instead of downloading the same version again",
+		Example: "govm cache [list|clean|prune]",
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"l"},
+		Short:   "List cached archives",
+		Long:    "List cached archives, most recently downloaded first",
+		Example: "govm cache list",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handler.List(ctx)
+		},
+	}
+
+	cleanCmd := &cobra.Command{
+		Use:     "clean",
+		Short:   "Remove all cached archives",
+		Long:    "Remove all cached archives",
+		Example: "govm cache clean",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := handler.Clean(ctx); err != nil {
+				return err
+			}
+			util.PrintSuccess("Cache cleaned successfully!")
+			return nil
+		},
+	}
+
+	var keep int
+
+	pruneCmd := &cobra.Command{
+		Use:     "prune",
+		Short:   "Remove old cached archives",
+		Long:    "Remove cached archives, keeping only the most recently downloaded ones",
+		Example: "govm cache prune [--keep N]",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := handler.Prune(ctx, keep)
+			if err != nil {
+				return err
+			}
+			util.PrintSuccess("%d archive(s) removed from cache!", removed)
+			return nil
+		},
+	}
+
+	pruneCmd.Flags().IntVarP(
+		&keep,
+		"keep",
+		"k",
+		1,
+		"Number of most recently downloaded archives to keep",
+	)
+
+	cacheCmd.AddCommand(listCmd, cleanCmd, pruneCmd)
+
+	return cacheCmd
+}
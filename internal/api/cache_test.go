@@ -0,0 +1,139 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type cacheCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.CacheHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestCacheCmd(t *testing.T) {
+	suite.Run(t, new(cacheCmdSuite))
+}
+
+func (r *cacheCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.CacheHandlerMock)
+	r.cmd = api.NewCacheCmd(r.ctx, r.handler)
+}
+
+func (r *cacheCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *cacheCmdSuite) TestListSuccess() {
+	// Arrange
+	r.handler.On("List", r.ctx).Return(nil)
+	r.cmd.SetArgs([]string{"list"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Empty(output)
+}
+
+func (r *cacheCmdSuite) TestListError() {
+	// Arrange
+	r.handler.On("List", r.ctx).Return(errors.New("list error"))
+	r.cmd.SetArgs([]string{"list"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.EqualError(err, "list error")
+	r.Empty(output)
+}
+
+func (r *cacheCmdSuite) TestCleanSuccess() {
+	// Arrange
+	r.handler.On("Clean", r.ctx).Return(nil)
+	r.cmd.SetArgs([]string{"clean"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("Cache cleaned successfully!\n", output)
+}
+
+func (r *cacheCmdSuite) TestCleanError() {
+	// Arrange
+	r.handler.On("Clean", r.ctx).Return(errors.New("clean error"))
+	r.cmd.SetArgs([]string{"clean"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.EqualError(err, "clean error")
+	r.Empty(output)
+}
+
+func (r *cacheCmdSuite) TestPruneSuccess() {
+	// Arrange
+	r.handler.On("Prune", r.ctx, 3).Return(2, nil)
+	r.cmd.SetArgs([]string{"prune", "--keep", "3"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("2 archive(s) removed from cache!\n", output)
+}
+
+func (r *cacheCmdSuite) TestPruneDefaultKeep() {
+	// Arrange
+	r.handler.On("Prune", r.ctx, 1).Return(0, nil)
+	r.cmd.SetArgs([]string{"prune"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("0 archive(s) removed from cache!\n", output)
+}
+
+func (r *cacheCmdSuite) TestPruneError() {
+	// Arrange
+	r.handler.On("Prune", r.ctx, 1).Return(0, errors.New("prune error"))
+	r.cmd.SetArgs([]string{"prune"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.EqualError(err, "prune error")
+	r.Empty(output)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCmd(ctx context.Context, handler handler.ConfigHandler) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Manage govm configuration",
+		Long:    "Manage the settings kept in ~/.govm/config.toml",
+		Example: "govm config [get|set|list|path]",
+	}
+
+	getCmd := &cobra.Command{
+		Use:       "get",
+		Short:     "Print the value of a setting",
+		Long:      "Print the value in effect for a setting, including defaults and environment overrides",
+		Example:   "govm config get [key]",
+		ValidArgs: domain.ConfigKeys(),
+		Args:      cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := handler.Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+			return nil
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:       "set",
+		Short:     "Change a setting",
+		Long:      "Change a setting in the config file, an empty value restores its default",
+		Example:   "govm config set [key] [value]",
+		ValidArgs: domain.ConfigKeys(),
+		Args:      cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := handler.Set(ctx, args[0], args[1]); err != nil {
+				return err
+			}
+			util.PrintSuccess("%s set to \"%s\" successfully!", args[0], args[1])
+			return nil
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"l"},
+		Short:   "List all settings",
+		Long:    "List all settings with the values in effect",
+		Example: "govm config list",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handler.List(ctx)
+		},
+	}
+
+	pathCmd := &cobra.Command{
+		Use:     "path",
+		Short:   "Print the config file path",
+		Long:    "Print the config file path",
+		Example: "govm config path",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := handler.Path(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Println(path)
+			return nil
+		},
+	}
+
+	configCmd.AddCommand(getCmd, setCmd, listCmd, pathCmd)
+
+	return configCmd
+}
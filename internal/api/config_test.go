@@ -0,0 +1,151 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type configCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.ConfigHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestConfigCmd(t *testing.T) {
+	suite.Run(t, new(configCmdSuite))
+}
+
+func (r *configCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.ConfigHandlerMock)
+	r.cmd = api.NewConfigCmd(r.ctx, r.handler)
+}
+
+func (r *configCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *configCmdSuite) TestGetSuccess() {
+	// Arrange
+	r.handler.On("Get", r.ctx, "mirror").Return("https://go.dev/dl", nil)
+	r.cmd.SetArgs([]string{"get", "mirror"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("https://go.dev/dl\n", output)
+}
+
+func (r *configCmdSuite) TestGetError() {
+	// Arrange
+	r.handler.On("Get", r.ctx, "proxy").Return("", errors.New("get error"))
+	r.cmd.SetArgs([]string{"get", "proxy"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.EqualError(err, "get error")
+	r.Empty(output)
+}
+
+func (r *configCmdSuite) TestSetSuccess() {
+	// Arrange
+	r.handler.On("Set", r.ctx, "color", "never").Return(nil)
+	r.cmd.SetArgs([]string{"set", "color", "never"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("color set to \"never\" successfully!\n", output)
+}
+
+func (r *configCmdSuite) TestSetError() {
+	// Arrange
+	r.handler.On("Set", r.ctx, "color", "rainbow").Return(errors.New("set error"))
+	r.cmd.SetArgs([]string{"set", "color", "rainbow"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.EqualError(err, "set error")
+	r.Empty(output)
+}
+
+func (r *configCmdSuite) TestSetInvalidArguments() {
+	// Arrange
+	r.cmd.SetArgs([]string{"set", "color"})
+
+	// Act
+	err := r.cmd.Execute()
+
+	// Assert
+	r.Error(err)
+	r.EqualError(err, "accepts 2 arg(s), received 1")
+}
+
+func (r *configCmdSuite) TestListSuccess() {
+	// Arrange
+	r.handler.On("List", r.ctx).Return(nil)
+	r.cmd.SetArgs([]string{"list"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Empty(output)
+}
+
+func (r *configCmdSuite) TestPathSuccess() {
+	// Arrange
+	r.handler.On("Path", r.ctx).Return("/home/fake/.govm/config.toml", nil)
+	r.cmd.SetArgs([]string{"path"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("/home/fake/.govm/config.toml\n", output)
+}
+
+func (r *configCmdSuite) TestPathError() {
+	// Arrange
+	r.handler.On("Path", r.ctx).Return("", errors.New("path error"))
+	r.cmd.SetArgs([]string{"path"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.EqualError(err, "path error")
+	r.Empty(output)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+func NewExecCmd(ctx context.Context, handler handler.ExecHandler) *cobra.Command {
+	execCmd := &cobra.Command{
+		Use:     "exec",
+		Short:   "Run a command with an installed Go version",
+		Long:    "Run a command with GOROOT and PATH pointing to an installed Go version, without changing the current version",
+		Example: "govm exec [version] -- [command] [args...]",
+		Args: func(cmd *cobra.Command, args []string) error {
+			return cobra.MinimumNArgs(2)(cmd, execArgs(args))
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args = execArgs(args)
+			code, err := handler.Handle(ctx, &domain.Action{Version: args[0]}, args[1], args[2:])
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				return domain.NewCommandExitError(code)
+			}
+			return nil
+		},
+	}
+
+	// Flags after the version belong to the command being run, not to govm.
+	execCmd.Flags().SetInterspersed(false)
+
+	return execCmd
+}
+
+// execArgs drops the "--" separating the version from the command, which is
+// kept in args because flag parsing stops at the first positional argument.
+func execArgs(args []string) []string {
+	if len(args) > 1 && args[1] == "--" {
+		return append([]string{args[0]}, args[2:]...)
+	}
+	return args
+}
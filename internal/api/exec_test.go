@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type execCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.ExecHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestExecCmd(t *testing.T) {
+	suite.Run(t, new(execCmdSuite))
+}
+
+func (r *execCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.ExecHandlerMock)
+	r.cmd = api.NewExecCmd(r.ctx, r.handler)
+}
+
+func (r *execCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *execCmdSuite) TestSuccess() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.0"}, "go", []string{"test", "-v", "./..."}).Return(0, nil)
+	r.cmd.SetArgs([]string{"go1.21.0", "--", "go", "test", "-v", "./..."})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Empty(output)
+}
+
+func (r *execCmdSuite) TestSuccessWithoutDash() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.0"}, "go", []string{"vet", "-all"}).Return(0, nil)
+	r.cmd.SetArgs([]string{"go1.21.0", "go", "vet", "-all"})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Empty(output)
+}
+
+func (r *execCmdSuite) TestMissingCommand() {
+	// Arrange
+	r.cmd.SetArgs([]string{"go1.21.0", "--"})
+
+	// Act
+	err := r.cmd.Execute()
+
+	// Assert
+	r.Error(err)
+	r.EqualError(err, "requires at least 2 arg(s), only received 1")
+}
+
+func (r *execCmdSuite) TestInvalidArguments() {
+	// Arrange
+	r.cmd.SetArgs([]string{"go1.21.0"})
+
+	// Act
+	err := r.cmd.Execute()
+
+	// Assert
+	r.Error(err)
+	r.EqualError(err, "requires at least 2 arg(s), only received 1")
+}
+
+func (r *execCmdSuite) TestCommandFailure() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.0"}, "go", []string{"test", "-v", "./..."}).Return(2, nil)
+	r.cmd.SetArgs([]string{"go1.21.0", "--", "go", "test", "-v", "./..."})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.Equal(domain.NewCommandExitError(2), err)
+	r.Equal(2, domain.ExitCode(err))
+	r.Empty(output)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+func NewHookCmd(ctx context.Context, handler handler.HookHandler) *cobra.Command {
+	return &cobra.Command{
+		Use:   "hook",
+		Short: "Print a shell hook that switches Go version on directory change",
+		Long: "Print a shell hook that, on every directory change, puts the Go version pinned by the nearest " +
+			".go-version or go.mod file in front of PATH for the current shell session only",
+		Example:   "eval \"$(govm hook bash)\"\neval \"$(govm hook zsh)\"\ngovm hook fish | source",
+		ValidArgs: domain.Shells(),
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			script, err := handler.Handle(ctx, domain.Shell(args[0]))
+			if err != nil {
+				return err
+			}
+			fmt.Println(script)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type hookCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.HookHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestHookCmd(t *testing.T) {
+	suite.Run(t, new(hookCmdSuite))
+}
+
+func (r *hookCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.HookHandlerMock)
+	r.cmd = api.NewHookCmd(r.ctx, r.handler)
+}
+
+func (r *hookCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *hookCmdSuite) TestSuccess() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, domain.BashShell).Return("_govm_hook", nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"bash"})
+	})
+
+	// Assert
+	r.Equal("_govm_hook\n", output)
+}
+
+func (r *hookCmdSuite) TestErrorHandling() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, domain.FishShell).Return("", errors.New("hook error"))
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"fish"})
+	})
+
+	// Assert
+	r.EqualError(err, "hook error")
+	r.Empty(output)
+}
+
+func (r *hookCmdSuite) TestInvalidArguments() {
+	// Arrange
+	r.cmd.SetArgs([]string{"csh"})
+
+	// Act
+	err := r.cmd.Execute()
+
+	// Assert
+	r.Error(err)
+	r.EqualError(err, "invalid argument \"csh\" for \"hook\"")
+}
@@ -2,6 +2,10 @@ package api
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
@@ -9,21 +13,99 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func NewInstallCmd(ctx context.Context, handler handler.InstallHandler) *cobra.Command {
-	return &cobra.Command{
+func NewInstallCmd(ctx context.Context, handler handler.InstallHandler, renderer util.Renderer) *cobra.Command {
+	var fromFileParam, fromURLParam, sha256Param, sourceParam, repoParam, osParam, archParam string
+	var unstableParam bool
+
+	installCmd := &cobra.Command{
 		Use:     "install",
 		Aliases: []string{"i"},
 		Short:   "Install a Go version",
-		Long:    "Install a Go version",
-		Example: "govm install [version]",
-		Args:    cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
-		Run: func(cmd *cobra.Command, args []string) {
-			if err := handler.Handle(ctx, &domain.Action{Version: args[0]}); err != nil {
-				util.PrintError(err.Error())
-				return
-			}
-			util.PrintSuccess("Go version \"%s\" installed successfully!", args[0])
-			util.PrintWarning("Please, reopen your terminal to start using new version.")
+		Long:    "Install a Go version. When no version is given, the version pinned by the nearest .go-version or go.mod file is installed. With --from-file or --from-url, the given archive is installed instead, its version read from its VERSION file. \"tip\" or --source builds Go from a git ref, bootstrapping from the current version",
+		Example: "govm install [version]\ngovm install --unstable go1.23rc1\ngovm install --from-file ./go1.22.3.linux-amd64.tar.gz --sha256 [checksum]\ngovm install tip\ngovm install --source release-branch.go1.23 --repo ~/src/go\ngovm install --os linux --arch arm64 [version]",
+		Args: cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs, func(cmd *cobra.Command, args []string) error {
+			fromArchive := fromFileParam != "" || fromURLParam != ""
+			if fromArchive && len(args) > 0 {
+				return errors.New("a version can't be given along with --from-file or --from-url")
+			}
+			if sourceParam != "" && len(args) > 0 {
+				return errors.New("a version can't be given along with --source")
+			}
+			if cmd.Flags().Changed("repo") && sourceParam == "" && (len(args) == 0 || args[0] != "tip") {
+				return errors.New("--repo requires --source or tip")
+			}
+			if !fromArchive && sha256Param != "" {
+				return errors.New("--sha256 requires --from-file or --from-url")
+			}
+			fromSource := sourceParam != "" || len(args) > 0 && args[0] == "tip"
+			if (osParam != "" || archParam != "") && (fromArchive || fromSource) {
+				return errors.New("--os and --arch can't be given along with --from-file, --from-url, --source or tip")
+			}
+			if osParam == "windows" {
+				return errors.New("windows toolchains are zip archives, which can't be installed yet")
+			}
+			return nil
+		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			install := &domain.Action{
+				ArchiveURL: fromURLParam,
+				Checksum:   strings.ToLower(sha256Param),
+				Unstable:   unstableParam,
+				OS:         osParam,
+				Arch:       archParam,
+			}
+			if len(args) > 0 {
+				install.Version = args[0]
+			}
+			if install.Version == "tip" {
+				sourceParam = domain.TipRef
+			}
+			if sourceParam != "" {
+				repo := repoParam
+				// A local checkout is fetched from within the staging directory.
+				if _, err := os.Stat(repo); err == nil {
+					repo, _ = filepath.Abs(repo)
+				}
+				install.Source = sourceParam
+				install.SourceRepo = repo
+				install.Version = domain.SourceVersion(sourceParam)
+			}
+			if fromFileParam != "" {
+				archive, err := filepath.Abs(fromFileParam)
+				if err != nil {
+					return err
+				}
+				install.Archive = archive
+			}
+			if err := handler.Handle(ctx, install); err != nil {
+				return err
+			}
+			renderer.Result(domain.NewInstallResult(*install), func() {
+				if target := install.Target(); !target.IsHost() {
+					util.PrintSuccess("Go version \"%s\" for %s installed successfully!", install.Version, target)
+				} else {
+					util.PrintSuccess("Go version \"%s\" installed successfully!", install.Version)
+				}
+				if install.SignatureUnverified {
+					util.PrintWarning("The signature of the archive could not be verified, see govm.log for details.")
+				}
+				if install.PathUpdated {
+					util.PrintWarning("Please, reopen your terminal to start using new version.")
+				}
+			})
+			return nil
 		},
 	}
+
+	installCmd.Flags().StringVar(&fromFileParam, "from-file", "", "Install the Go archive at this path instead of downloading it")
+	installCmd.Flags().StringVar(&fromURLParam, "from-url", "", "Install the Go archive downloaded from this URL")
+	installCmd.Flags().StringVar(&sha256Param, "sha256", "", "Expected SHA256 checksum of the archive given with --from-file or --from-url")
+	installCmd.Flags().BoolVar(&unstableParam, "unstable", false, "Allow installing a release candidate or beta")
+	installCmd.Flags().StringVar(&sourceParam, "source", "", "Build Go from this git branch, tag or commit")
+	installCmd.Flags().StringVar(&repoParam, "repo", domain.GoSourceRepo, "Repository or local checkout to build Go from")
+	installCmd.Flags().StringVar(&osParam, "os", "", "Install the toolchain for this operating system instead of the host's")
+	installCmd.Flags().StringVar(&archParam, "arch", "", "Install the toolchain for this architecture instead of the host's")
+	installCmd.MarkFlagsMutuallyExclusive("from-file", "from-url", "source")
+
+	return installCmd
 }
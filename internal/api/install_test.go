@@ -2,14 +2,18 @@ package api_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/api"
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/test"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -17,6 +21,7 @@ type installCmdSuite struct {
 	suite.Suite
 	ctx     context.Context
 	handler *handler.InstallHandlerMock
+	render  util.RenderConfig
 	cmd     *cobra.Command
 }
 
@@ -27,7 +32,8 @@ func TestInstallCmd(t *testing.T) {
 func (r *installCmdSuite) SetupTest() {
 	r.ctx = context.Background()
 	r.handler = new(handler.InstallHandlerMock)
-	r.cmd = api.NewInstallCmd(r.ctx, r.handler)
+	r.render.Format = domain.TextOutput
+	r.cmd = api.NewInstallCmd(r.ctx, r.handler, util.NewRenderer(&r.render))
 }
 
 func (r *installCmdSuite) TearDownTest() {
@@ -36,40 +42,243 @@ func (r *installCmdSuite) TearDownTest() {
 
 func (r *installCmdSuite) TestSuccess() {
 	// Arrange
-	r.handler.On("Handle", r.ctx, &domain.Action{Version: "1.15.0"}).Return(nil)
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "1.15.0"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).PathUpdated = true }).
+		Return(nil)
 
 	// Act
 	output, _ := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{"1.15.0"})
-		return nil
+		return r.cmd.RunE(r.cmd, []string{"1.15.0"})
 	})
 
 	// Assert
 	r.Equal("Go version \"1.15.0\" installed successfully!\nPlease, reopen your terminal to start using new version.\n", output)
 }
 
+func (r *installCmdSuite) TestSuccessWithUnverifiedSignature() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "1.15.0"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).SignatureUnverified = true }).
+		Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"1.15.0"})
+	})
+
+	// Assert
+	r.Equal("Go version \"1.15.0\" installed successfully!\nThe signature of the archive could not be verified, see govm.log for details.\n", output)
+}
+
+func (r *installCmdSuite) TestSuccessAsJSON() {
+	// Arrange
+	r.render.Format = domain.JSONOutput
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "1.15.0", OS: "linux", Arch: "arm64"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).Checksum = "abc123" }).
+		Return(nil)
+	r.NoError(r.cmd.Flags().Set("os", "linux"))
+	r.NoError(r.cmd.Flags().Set("arch", "arm64"))
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"1.15.0"})
+	})
+
+	// Assert
+	var actual domain.InstallResult
+	r.NoError(json.Unmarshal([]byte(output), &actual))
+	r.Equal(domain.InstallResult{
+		Version:  "1.15.0",
+		Platform: "linux/arm64",
+		Path:     domain.Action{Version: "1.15.0", OS: "linux", Arch: "arm64"}.HomeVersionDir(),
+		Checksum: "abc123",
+	}, actual)
+	r.Contains(output, `"checksum": "abc123"`)
+	r.Contains(output, `"path_updated": false`)
+}
+
+func (r *installCmdSuite) TestSuccessFromFile() {
+	// Arrange
+	archive, _ := filepath.Abs("go1.22.3.linux-amd64.tar.gz")
+	r.NoError(r.cmd.Flags().Set("from-file", "go1.22.3.linux-amd64.tar.gz"))
+	r.NoError(r.cmd.Flags().Set("sha256", "ABC123"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Archive: archive, Checksum: "abc123"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).Version = "go1.22.3" }).
+		Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal("Go version \"go1.22.3\" installed successfully!\n", output)
+}
+
+func (r *installCmdSuite) TestSuccessUnstable() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("unstable", "true"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.23rc1", Unstable: true}).Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.23rc1"})
+	})
+
+	// Assert
+	r.Equal("Go version \"go1.23rc1\" installed successfully!\n", output)
+}
+
+func (r *installCmdSuite) TestSuccessTip() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "gotip", Source: domain.TipRef, SourceRepo: domain.GoSourceRepo}).Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"tip"})
+	})
+
+	// Assert
+	r.Equal("Go version \"gotip\" installed successfully!\n", output)
+}
+
+func (r *installCmdSuite) TestSuccessFromSource() {
+	// Arrange
+	repo := r.T().TempDir()
+	r.T().Chdir(filepath.Dir(repo))
+	r.NoError(r.cmd.Flags().Set("source", "release-branch.go1.23"))
+	r.NoError(r.cmd.Flags().Set("repo", filepath.Base(repo)))
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "gotip-release-branch.go1.23", Source: "release-branch.go1.23", SourceRepo: repo}).Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal("Go version \"gotip-release-branch.go1.23\" installed successfully!\n", output)
+}
+
+func (r *installCmdSuite) TestSuccessForTarget() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("os", "plan9"))
+	r.NoError(r.cmd.Flags().Set("arch", "arm"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.22.3", OS: "plan9", Arch: "arm"}).Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.22.3"})
+	})
+
+	// Assert
+	r.Equal("Go version \"go1.22.3\" for plan9/arm installed successfully!\n", output)
+}
+
+func (r *installCmdSuite) TestTargetWithSource() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("arch", "arm64"))
+
+	// Act
+	err := r.cmd.Args(r.cmd, []string{"tip"})
+
+	// Assert
+	r.EqualError(err, "--os and --arch can't be given along with --from-file, --from-url, --source or tip")
+}
+
+func (r *installCmdSuite) TestTargetWindows() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("os", "windows"))
+
+	// Act
+	err := r.cmd.Args(r.cmd, []string{"go1.22.3"})
+
+	// Assert
+	r.EqualError(err, "windows toolchains are zip archives, which can't be installed yet")
+}
+
+func (r *installCmdSuite) TestVersionWithSource() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("source", "master"))
+
+	// Act
+	err := r.cmd.Args(r.cmd, []string{"1.22.3"})
+
+	// Assert
+	r.EqualError(err, "a version can't be given along with --source")
+}
+
+func (r *installCmdSuite) TestRepoWithoutSource() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("repo", "/home/fake/go"))
+
+	// Act
+	err := r.cmd.Args(r.cmd, []string{"1.22.3"})
+	tipErr := r.cmd.Args(r.cmd, []string{"tip"})
+
+	// Assert
+	r.EqualError(err, "--repo requires --source or tip")
+	r.NoError(tipErr)
+}
+
+func (r *installCmdSuite) TestVersionWithArchive() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("from-url", "https://example.com/go1.22.3.linux-amd64.tar.gz"))
+
+	// Act
+	err := r.cmd.Args(r.cmd, []string{"1.22.3"})
+
+	// Assert
+	r.EqualError(err, "a version can't be given along with --from-file or --from-url")
+}
+
+func (r *installCmdSuite) TestChecksumWithoutArchive() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("sha256", "abc123"))
+
+	// Act
+	err := r.cmd.Args(r.cmd, []string{"1.22.3"})
+
+	// Assert
+	r.EqualError(err, "--sha256 requires --from-file or --from-url")
+}
+
+func (r *installCmdSuite) TestSuccessWithPinnedVersion() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).Version = "go1.22.3" }).
+		Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal("Go version \"go1.22.3\" installed successfully!\n", output)
+}
+
 func (r *installCmdSuite) TestErrorHandling() {
 	// Arrange
 	r.handler.On("Handle", r.ctx, &domain.Action{Version: "1.24.0"}).Return(errors.New("install error"))
 
 	// Act
-	output, _ := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{"1.24.0"})
-		return nil
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"1.24.0"})
 	})
 
 	// Assert
-	r.Equal("install error\n", output)
+	r.EqualError(err, "install error")
+	r.Empty(output)
 }
 
 func (r *installCmdSuite) TestInvalidArguments() {
 	// Arrange
-	r.cmd.SetArgs([]string{})
+	r.cmd.SetArgs([]string{"go1.22.3", "go1.21.0"})
 
 	// Act
 	err := r.cmd.Execute()
 
 	// Assert
 	r.Error(err)
-	r.EqualError(err, "accepts 1 arg(s), received 0")
+	r.EqualError(err, "accepts at most 1 arg(s), received 2")
 }
@@ -2,23 +2,64 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/util"
 	"github.com/spf13/cobra"
 )
 
-func NewListCmd(ctx context.Context, handler handler.ListHandler) *cobra.Command {
-	return &cobra.Command{
+func NewListCmd(ctx context.Context, handler handler.ListHandler, renderer util.Renderer) *cobra.Command {
+	var unstableParam bool
+	var osParam, archParam string
+
+	listCmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"l"},
 		Short:   "List all Go versions",
 		Long:    "List all Go versions",
-		Example: "govm list",
-		Run: func(cmd *cobra.Command, args []string) {
-			if err := handler.Handle(ctx); err != nil {
-				util.PrintError(err.Error())
+		Example: "govm list\ngovm list --unstable\ngovm list --os linux --arch arm64\ngovm list --output json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := handler.Handle(ctx, &domain.Action{Unstable: unstableParam, OS: osParam, Arch: archParam})
+			if err != nil {
+				return err
 			}
+			renderer.Result(result, func() { printVersionList(result) })
+			return nil
 		},
 	}
+
+	listCmd.Flags().BoolVar(&unstableParam, "unstable", false, "Include release candidates and betas")
+	listCmd.Flags().StringVar(&osParam, "os", "", "List the versions for this operating system instead of the host's")
+	listCmd.Flags().StringVar(&archParam, "arch", "", "List the versions for this architecture instead of the host's")
+
+	return listCmd
+}
+
+// printVersionList prints the versions in columns, top to bottom.
+func printVersionList(list domain.VersionListResult) {
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("Available Go versions for %s \n", list.Platform)
+	fmt.Println(strings.Repeat("=", 100))
+
+	numCols := 6
+	maxRows := (len(list.Versions) + numCols - 1) / numCols
+
+	for i := 0; i < maxRows; i++ {
+		var row []string
+		for j := 0; j < numCols; j++ {
+			idx := i + j*maxRows
+			if idx < len(list.Versions) {
+				row = append(row, fmt.Sprintf("%-15s", list.Versions[idx].String()))
+			}
+		}
+		fmt.Println(strings.Join(row, ""))
+	}
+
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Println("* currently in use")
+	fmt.Println("+ installed")
+	fmt.Println(strings.Repeat("=", 100))
 }
@@ -2,12 +2,16 @@ package api_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/test"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/suite"
 )
@@ -16,9 +20,22 @@ type listCmdSuite struct {
 	suite.Suite
 	ctx     context.Context
 	handler *handler.ListHandlerMock
+	render  util.RenderConfig
 	cmd     *cobra.Command
 }
 
+var versionList = domain.VersionListResult{
+	Platform: "linux/amd64",
+	Versions: []domain.VersionResult{
+		{Version: "1.16", Stable: true},
+		{Version: "1.17", Stable: true, Installed: true},
+		{Version: "1.18", Stable: true},
+		{Version: "1.19", Stable: true},
+		{Version: "1.20", Stable: true, Installed: true, Active: true},
+		{Version: "1.21rc1"},
+	},
+}
+
 func TestListCmd(t *testing.T) {
 	suite.Run(t, new(listCmdSuite))
 }
@@ -26,7 +43,8 @@ func TestListCmd(t *testing.T) {
 func (r *listCmdSuite) SetupTest() {
 	r.ctx = context.Background()
 	r.handler = new(handler.ListHandlerMock)
-	r.cmd = api.NewListCmd(r.ctx, r.handler)
+	r.render.Format = domain.TextOutput
+	r.cmd = api.NewListCmd(r.ctx, r.handler, util.NewRenderer(&r.render))
 }
 
 func (r *listCmdSuite) TearDownTest() {
@@ -35,30 +53,91 @@ func (r *listCmdSuite) TearDownTest() {
 
 func (r *listCmdSuite) TestSuccess() {
 	// Arrange
-	r.handler.On("Handle", r.ctx).Return(nil)
+	r.handler.On("Handle", r.ctx, &domain.Action{}).Return(versionList, nil)
 	r.cmd.SetArgs([]string{})
 
 	// Act
 	output, _ := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{})
-		return nil
+		return r.cmd.RunE(r.cmd, []string{})
 	})
 
 	// Assert
-	r.Empty(output)
+	expected := strings.Join(
+		[]string{
+			strings.Repeat("=", 100) + "\n",
+			"Available Go versions for linux/amd64 \n",
+			strings.Repeat("=", 100) + "\n",
+			"1.16           + 1.17         1.18           1.19           * 1.20         1.21rc1        \n",
+			strings.Repeat("=", 100) + "\n",
+			"* currently in use\n",
+			"+ installed\n",
+			strings.Repeat("=", 100) + "\n",
+		},
+		"",
+	)
+	r.Equal(expected, output)
+}
+
+func (r *listCmdSuite) TestSuccessAsJSON() {
+	// Arrange
+	r.render.Format = domain.JSONOutput
+	r.handler.On("Handle", r.ctx, &domain.Action{}).Return(versionList, nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	var actual domain.VersionListResult
+	r.NoError(json.Unmarshal([]byte(output), &actual))
+	r.Equal(versionList, actual)
+	r.Contains(output, `"version": "1.20",
+      "stable": true,
+      "installed": true,
+      "active": true`)
+}
+
+func (r *listCmdSuite) TestUnstable() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("unstable", "true"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Unstable: true}).Return(domain.VersionListResult{}, nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Contains(output, "Available Go versions for")
+}
+
+func (r *listCmdSuite) TestTarget() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("os", "linux"))
+	r.NoError(r.cmd.Flags().Set("arch", "arm64"))
+	r.handler.On("Handle", r.ctx, &domain.Action{OS: "linux", Arch: "arm64"}).Return(domain.VersionListResult{}, nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Contains(output, "Available Go versions for")
 }
 
 func (r *listCmdSuite) TestErrorHandling() {
 	// Arrange
-	r.handler.On("Handle", r.ctx).Return(errors.New("list error"))
+	r.handler.On("Handle", r.ctx, &domain.Action{}).Return(domain.VersionListResult{}, errors.New("list error"))
 	r.cmd.SetArgs([]string{})
 
 	// Act
-	output, _ := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{})
-		return nil
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
 	})
 
 	// Assert
-	r.Equal("list error\n", output)
+	r.EqualError(err, "list error")
+	r.Empty(output)
 }
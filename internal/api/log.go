@@ -18,7 +18,7 @@ func NewLogCmd(ctx context.Context) *cobra.Command {
 		Short:   "Show log info",
 		Long:    "Show log info",
 		Example: "govm log",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			logFilePath := path.Join(os.TempDir(), "govm.log")
 			if _, err := os.Stat(logFilePath); err == nil {
 				fmt.Println(strings.Repeat("=", 100))
@@ -27,19 +27,20 @@ func NewLogCmd(ctx context.Context) *cobra.Command {
 
 				logFile, err := os.Open(logFilePath)
 				if err != nil {
-					util.PrintError("Failed to open log file")
-					os.Exit(1)
+					return fmt.Errorf("failed to open log file: %w", err)
 				}
+				defer logFile.Close()
 
 				scanner := bufio.NewScanner(logFile)
 				for scanner.Scan() {
 					fmt.Println(scanner.Text())
 				}
 
-				return
+				return scanner.Err()
 			}
 
 			util.PrintWarning("No log entries available.")
+			return nil
 		},
 	}
 }
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+func NewResolveCmd(ctx context.Context, handler handler.ResolveHandler) *cobra.Command {
+	var binParam bool
+
+	resolveCmd := &cobra.Command{
+		Use:     "resolve",
+		Short:   "Print the Go version pinned for the current directory",
+		Long:    "Print the installed Go version pinned by the nearest .go-version or go.mod file",
+		Example: "govm resolve [--bin]",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resolve := &domain.Action{}
+			if err := handler.Handle(ctx, resolve); err != nil {
+				return err
+			}
+			if binParam {
+				fmt.Println(resolve.HomeVersionBinDir())
+				return nil
+			}
+			fmt.Println(resolve.Version)
+			return nil
+		},
+	}
+
+	resolveCmd.Flags().BoolVarP(
+		&binParam,
+		"bin",
+		"b",
+		false,
+		"Print the bin directory of the resolved version instead of its name",
+	)
+
+	return resolveCmd
+}
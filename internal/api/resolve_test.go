@@ -0,0 +1,87 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type resolveCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.ResolveHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestResolveCmd(t *testing.T) {
+	suite.Run(t, new(resolveCmdSuite))
+}
+
+func (r *resolveCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.ResolveHandlerMock)
+	r.cmd = api.NewResolveCmd(r.ctx, r.handler)
+}
+
+func (r *resolveCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *resolveCmdSuite) resolveTo(version string) {
+	r.handler.On("Handle", r.ctx, &domain.Action{}).
+		Run(func(args mock.Arguments) {
+			action := args.Get(1).(*domain.Action)
+			action.Version = version
+			action.HomeDir = "/home/fake"
+		}).
+		Return(nil)
+}
+
+func (r *resolveCmdSuite) TestSuccess() {
+	// Arrange
+	r.resolveTo("go1.22.3")
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal("go1.22.3\n", output)
+}
+
+func (r *resolveCmdSuite) TestSuccessBinDir() {
+	// Arrange
+	r.resolveTo("go1.22.3")
+	r.cmd.Flags().Set("bin", "true")
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal("/home/fake/.govm/versions/go1.22.3/bin\n", output)
+}
+
+func (r *resolveCmdSuite) TestErrorHandling() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{}).Return(errors.New("resolve error"))
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.EqualError(err, "resolve error")
+	r.Empty(output)
+}
@@ -3,13 +3,17 @@ package api
 import (
 	"context"
 	"fmt"
+	"os"
 	"runtime"
 	"sync"
 
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
@@ -20,7 +24,9 @@ var (
 func NewRootCmd(
 	ctx context.Context,
 	version string,
-	httpGateway gateway.HttpGateway, 
+	config *domain.Config,
+	httpConfig *gateway.HttpConfig,
+	render *util.RenderConfig,
 	osGateway gateway.OsGateway,
 ) *cobra.Command {
 	once.Do(func() {
@@ -29,16 +35,52 @@ func NewRootCmd(
 				Use:     "govm",
 				Short:   "::: Go Version Manager :::",
 				Version: fmt.Sprintf("%s %s/%s", version, runtime.GOOS, runtime.GOARCH),
+				// Errors are rendered by the caller of Execute, which exits with their code.
+				SilenceErrors: true,
+				// The usage only helps with invalid arguments, which are checked before.
+				PersistentPreRun: func(cmd *cobra.Command, args []string) {
+					cmd.SilenceUsage = true
+				},
 			}
 
-			sharedSvc := service.NewShared(httpGateway, osGateway)
+			instance.PersistentFlags().BoolVar(
+				&httpConfig.Offline,
+				"offline",
+				false,
+				"Use only the cached release index and archives, without network access",
+			)
+
+			instance.PersistentFlags().VarP(
+				&render.Format,
+				"output",
+				"o",
+				"Output format of list, install, update and uninstall: text, json or yaml",
+			)
+
+			instance.PersistentFlags().BoolVar(
+				&render.Verbose,
+				"verbose",
+				false,
+				"Show the causes of errors",
+			)
+
+			sharedSvc := service.NewShared(gateway.NewHttpGateway(httpConfig), osGateway, config)
+			progress := util.NewProgress(os.Stdout, term.IsTerminal(int(os.Stdout.Fd())))
+			renderer := util.NewRenderer(render)
 
 			instance.AddCommand(
-				NewListCmd(ctx, handler.NewList(sharedSvc)),
-				NewInstallCmd(ctx, handler.NewInstall(sharedSvc)),
-				NewUninstallCmd(ctx, handler.NewUninstall(sharedSvc)),
-				NewUpdateCmd(ctx, handler.NewUpdate(sharedSvc)),
+				NewListCmd(ctx, handler.NewList(sharedSvc), renderer),
+				NewInstallCmd(ctx, handler.NewInstall(sharedSvc, progress), renderer),
+				NewUninstallCmd(ctx, handler.NewUninstall(sharedSvc, progress), renderer, term.IsTerminal(int(os.Stdin.Fd()))),
+				NewUpdateCmd(ctx, handler.NewUpdate(sharedSvc, progress), renderer, config.Strategy()),
+				NewUseCmd(ctx, handler.NewUse(sharedSvc, progress)),
 				NewLogCmd(ctx),
+				NewHookCmd(ctx, handler.NewHook()),
+				NewResolveCmd(ctx, handler.NewResolve(sharedSvc)),
+				NewCacheCmd(ctx, handler.NewCache(sharedSvc)),
+				NewConfigCmd(ctx, handler.NewConfig(sharedSvc, config)),
+				NewExecCmd(ctx, handler.NewExec(sharedSvc)),
+				NewShimCmd(ctx, handler.NewShim(sharedSvc)),
 			)
 		}
 	})
@@ -8,8 +8,10 @@ import (
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
 	"github.com/sbonaiva/govm/internal/test"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +19,9 @@ func TestRootCmd(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
 
-	cmd := api.NewRootCmd(ctx, "dev", new(gateway.HttpGatewayMock), new(gateway.OsGatewayMock))
+	render := &util.RenderConfig{Format: domain.TextOutput}
+
+	cmd := api.NewRootCmd(ctx, "dev", &domain.Config{}, &gateway.HttpConfig{}, render, new(gateway.OsGatewayMock))
 
 	// Act
 	actual, err := test.CaptureOutput(func() error {
@@ -29,16 +33,25 @@ func TestRootCmd(t *testing.T) {
 		"Usage:\n",
 		"  govm [command]\n\n",
 		"Available Commands:\n",
+		"  cache       Manage downloaded Go archives\n",
 		"  completion  Generate the autocompletion script for the specified shell\n",
+		"  config      Manage govm configuration\n",
+		"  exec        Run a command with an installed Go version\n",
 		"  help        Help about any command\n",
+		"  hook        Print a shell hook that switches Go version on directory change\n",
 		"  install     Install a Go version\n",
 		"  list        List all Go versions\n",
 		"  log         Show log info\n",
+		"  resolve     Print the Go version pinned for the current directory\n",
 		"  uninstall   Uninstall a Go version\n",
-		"  update      Update Go version\n\n",
+		"  update      Update Go version\n",
+		"  use         Switch to an installed Go version\n\n",
 		"Flags:\n",
-		"  -h, --help      help for govm\n",
-		"  -v, --version   version for govm\n\n",
+		"  -h, --help            help for govm\n",
+		"      --offline         Use only the cached release index and archives, without network access\n",
+		"  -o, --output format   Output format of list, install, update and uninstall: text, json or yaml (default text)\n",
+		"      --verbose         Show the causes of errors\n",
+		"  -v, --version         version for govm\n\n",
 		"Use \"govm [command] --help\" for more information about a command.\n",
 	}, "")
 
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+const ShimCmd = "shim"
+
+func NewShimCmd(ctx context.Context, handler handler.ShimHandler) *cobra.Command {
+	return &cobra.Command{
+		Use:                ShimCmd,
+		Short:              "Run a Go tool with the version resolved for the current directory",
+		Long:               "Run a Go tool with the version selected by GOVM_VERSION, the nearest .go-version or go.mod file, or the current version",
+		Example:            "govm shim go version",
+		Hidden:             true,
+		DisableFlagParsing: true,
+		Args:               cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			code, err := handler.Handle(ctx, &domain.Action{}, args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				return domain.NewCommandExitError(code)
+			}
+			return nil
+		},
+	}
+}
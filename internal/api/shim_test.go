@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type shimCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.ShimHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestShimCmd(t *testing.T) {
+	suite.Run(t, new(shimCmdSuite))
+}
+
+func (r *shimCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.ShimHandlerMock)
+	r.cmd = api.NewShimCmd(r.ctx, r.handler)
+}
+
+func (r *shimCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *shimCmdSuite) TestSuccess() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{}, "go", []string{"test", "-v", "./..."}).Return(0, nil)
+	r.cmd.SetArgs([]string{"go", "test", "-v", "./..."})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Empty(output)
+}
+
+func (r *shimCmdSuite) TestCommandFailure() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{}, "go", []string{"test", "-v", "./..."}).Return(2, nil)
+	r.cmd.SetArgs([]string{"go", "test", "-v", "./..."})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.Execute()
+	})
+
+	// Assert
+	r.Equal(domain.NewCommandExitError(2), err)
+	r.Equal(2, domain.ExitCode(err))
+	r.Empty(output)
+}
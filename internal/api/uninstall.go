@@ -13,41 +13,87 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func NewUninstallCmd(ctx context.Context, handler handler.UninstallHandler) *cobra.Command {
-	return &cobra.Command{
-		Use:     "uninstall",
+// NewUninstallCmd asks for confirmation on stdin unless --yes is given. When stdin
+// isn't interactive, there is no one to ask, so --yes is required.
+func NewUninstallCmd(ctx context.Context, handler handler.UninstallHandler, renderer util.Renderer, interactive bool) *cobra.Command {
+	var yesParam bool
+	var osParam, archParam string
+
+	uninstallCmd := &cobra.Command{
+		Use:     "uninstall [version...]",
 		Aliases: []string{"u"},
 		Short:   "Uninstall a Go version",
-		Long:    "Uninstall a Go version",
-		Example: "govm uninstall",
-		Run: func(cmd *cobra.Command, args []string) {
-			reader := bufio.NewReader(os.Stdin)
-			for {
-				fmt.Print("Confirm uninstall current Go version? (y/n): ")
-				confirmation, _ := reader.ReadString('\n')
-				confirmation = strings.TrimSpace(confirmation)
-
-				if confirmation == "y" {
-					break
+		Long:    "Uninstall the given Go versions or, when none is given, the current one. Only uninstalling the current version unlinks it and removes govm from your shell rc files",
+		Example: "govm uninstall\ngovm uninstall go1.21.13 go1.22.8 --yes\ngovm uninstall --os linux --arch arm64 go1.23.6",
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yesParam {
+				if !interactive {
+					return domain.NewConfirmationRequiredError(cmd.Name())
+				}
+
+				question := "Confirm uninstall current Go version? (y/n): "
+				if len(args) > 0 {
+					question = fmt.Sprintf("Confirm uninstall Go version %s? (y/n): ", strings.Join(args, ", "))
+				}
+				if !confirm(question) {
+					return domain.NewAbortedError()
 				}
+			}
 
-				if confirmation == "n" {
-					util.PrintWarning("Uninstall aborted by user")
-					return
+			if len(args) == 0 {
+				uninstall := &domain.Action{OS: osParam, Arch: archParam}
+				if err := handler.Handle(ctx, uninstall); err != nil {
+					return err
 				}
+				result := domain.UninstallResult{Platform: uninstall.Target().String(), Versions: []string{uninstall.Version}}
+				renderer.Result(result, func() { util.PrintSuccess("Go uninstalled successfully!") })
+				return nil
+			}
 
-				util.PrintError("Invalid option, please type 'y' or 'n'")
-				continue
+			// Each version is reported as soon as it's removed, so a failure leaves no
+			// doubt about the ones removed before it. Data is rendered once at the end.
+			result := domain.UninstallResult{Platform: domain.Action{OS: osParam, Arch: archParam}.Target().String()}
+			for _, version := range args {
+				if err := handler.Handle(ctx, &domain.Action{Version: version, OS: osParam, Arch: archParam}); err != nil {
+					return err
+				}
+				result.Versions = append(result.Versions, version)
+				if !renderer.Structured() {
+					util.PrintSuccess("Go version \"%s\" uninstalled successfully!", version)
+				}
 			}
-			if err := handler.Handle(
-				ctx,
-				&domain.Action{},
-			); err != nil {
-				util.PrintError(err.Error())
-				return
+			if renderer.Structured() {
+				renderer.Result(result, nil)
 			}
-			util.PrintSuccess("Go uninstalled successfully!")
-			util.PrintWarning("Please, reopen your terminal if you want to install a new version.")
+			return nil
 		},
 	}
+
+	uninstallCmd.Flags().BoolVarP(&yesParam, "yes", "y", false, "Uninstall without asking for confirmation")
+	uninstallCmd.Flags().StringVar(&osParam, "os", "", "Uninstall toolchains for this operating system instead of the host's")
+	uninstallCmd.Flags().StringVar(&archParam, "arch", "", "Uninstall toolchains for this architecture instead of the host's")
+
+	return uninstallCmd
+}
+
+// confirm asks question until it's answered with "y" or "n". Reaching the end of
+// stdin counts as "n".
+func confirm(question string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print(question)
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+
+		if answer == "y" {
+			return true
+		}
+
+		if answer == "n" || err != nil {
+			return false
+		}
+
+		util.PrintError("Invalid option, please type 'y' or 'n'")
+	}
 }
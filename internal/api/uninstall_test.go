@@ -10,7 +10,9 @@ import (
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/test"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -18,6 +20,7 @@ type uninstallCmdSuite struct {
 	suite.Suite
 	ctx     context.Context
 	handler *handler.UninstallHandlerMock
+	render  util.RenderConfig
 	cmd     *cobra.Command
 }
 
@@ -28,7 +31,8 @@ func TestUninstallCmd(t *testing.T) {
 func (r *uninstallCmdSuite) SetupTest() {
 	r.ctx = context.Background()
 	r.handler = new(handler.UninstallHandlerMock)
-	r.cmd = api.NewUninstallCmd(r.ctx, r.handler)
+	r.render.Format = domain.TextOutput
+	r.cmd = api.NewUninstallCmd(r.ctx, r.handler, util.NewRenderer(&r.render), true)
 }
 
 func (r *uninstallCmdSuite) TearDownTest() {
@@ -56,13 +60,12 @@ func (r *uninstallCmdSuite) TestSuccess() {
 
 	// Act
 	output, err := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{})
-		return nil
+		return r.cmd.RunE(r.cmd, []string{})
 	})
 
 	// Assert
 	r.NoError(err)
-	r.Equal("Confirm uninstall current Go version? (y/n): Go uninstalled successfully!\nPlease, reopen your terminal if you want to install a new version.\n", output)
+	r.Equal("Confirm uninstall current Go version? (y/n): Go uninstalled successfully!\n", output)
 }
 
 func (r *uninstallCmdSuite) TestError() {
@@ -86,13 +89,12 @@ func (r *uninstallCmdSuite) TestError() {
 
 	// Act
 	output, err := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{})
-		return nil
+		return r.cmd.RunE(r.cmd, []string{})
 	})
 
 	// Assert
-	r.NoError(err)
-	r.Equal("Confirm uninstall current Go version? (y/n): uninstall error\n", output)
+	r.EqualError(err, "uninstall error")
+	r.Equal("Confirm uninstall current Go version? (y/n): ", output)
 }
 
 func (r *uninstallCmdSuite) TestAbort() {
@@ -114,11 +116,145 @@ func (r *uninstallCmdSuite) TestAbort() {
 
 	// Act
 	output, err := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{})
-		return nil
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal(domain.NewAbortedError(), err)
+	r.Equal("Confirm uninstall current Go version? (y/n): ", output)
+}
+
+func (r *uninstallCmdSuite) TestAbortAtEndOfInput() {
+	// Arrange
+	rdr, wtr, err := os.Pipe()
+	if err != nil {
+		r.T().Fatal(err)
+	}
+	wtr.Close()
+
+	defer func(v *os.File) { os.Stdin = v }(os.Stdin)
+	os.Stdin = rdr
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal(domain.NewAbortedError(), err)
+	r.Equal("Confirm uninstall current Go version? (y/n): ", output)
+}
+
+func (r *uninstallCmdSuite) TestNotInteractive() {
+	// Arrange
+	cmd := api.NewUninstallCmd(r.ctx, r.handler, util.NewRenderer(&r.render), false)
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return cmd.RunE(cmd, []string{"go1.22.3"})
+	})
+
+	// Assert
+	r.Equal(domain.NewConfirmationRequiredError("uninstall"), err)
+	r.EqualError(err, "Error: stdin is not a terminal, pass --yes to uninstall without confirmation Code: 123")
+	r.Equal(domain.ExitFailure, domain.ExitCode(err))
+	r.Empty(output)
+	r.handler.AssertNotCalled(r.T(), "Handle")
+}
+
+func (r *uninstallCmdSuite) TestVersionsWithYes() {
+	// Arrange
+	cmd := api.NewUninstallCmd(r.ctx, r.handler, util.NewRenderer(&r.render), false)
+	r.NoError(cmd.Flags().Set("yes", "true"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.13"}).Return(nil).Once()
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.22.8"}).Return(nil).Once()
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return cmd.RunE(cmd, []string{"go1.21.13", "go1.22.8"})
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("Go version \"go1.21.13\" uninstalled successfully!\nGo version \"go1.22.8\" uninstalled successfully!\n", output)
+}
+
+func (r *uninstallCmdSuite) TestVersionsAsJSON() {
+	// Arrange
+	r.render.Format = domain.JSONOutput
+	r.NoError(r.cmd.Flags().Set("yes", "true"))
+	r.NoError(r.cmd.Flags().Set("os", "linux"))
+	r.NoError(r.cmd.Flags().Set("arch", "arm64"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.13", OS: "linux", Arch: "arm64"}).Return(nil).Once()
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.22.8", OS: "linux", Arch: "arm64"}).Return(nil).Once()
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.21.13", "go1.22.8"})
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("{\n  \"platform\": \"linux/arm64\",\n  \"versions\": [\n    \"go1.21.13\",\n    \"go1.22.8\"\n  ]\n}\n", output)
+}
+
+func (r *uninstallCmdSuite) TestCurrentAsYAML() {
+	// Arrange
+	r.render.Format = domain.YAMLOutput
+	r.NoError(r.cmd.Flags().Set("yes", "true"))
+	r.handler.On("Handle", r.ctx, &domain.Action{}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).Version = "go1.22.8" }).
+		Return(nil)
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal("platform: "+domain.HostPlatform().String()+"\nversions:\n    - go1.22.8\n", output)
+}
+
+func (r *uninstallCmdSuite) TestVersionsStopAtError() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("yes", "true"))
+	r.NoError(r.cmd.Flags().Set("arch", "arm64"))
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.13", Arch: "arm64"}).Return(errors.New("uninstall error")).Once()
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.21.13", "go1.22.8"})
+	})
+
+	// Assert
+	r.EqualError(err, "uninstall error")
+	r.Empty(output)
+}
+
+func (r *uninstallCmdSuite) TestConfirmVersions() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.21.13"}).Return(nil)
+
+	rdr, wtr, err := os.Pipe()
+	if err != nil {
+		r.T().Fatal(err)
+	}
+	_, err = wtr.Write([]byte("x\ny\n"))
+	if err != nil {
+		r.T().Fatal(err)
+	}
+	wtr.Close()
+
+	defer func(v *os.File) { os.Stdin = v }(os.Stdin)
+	os.Stdin = rdr
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.21.13"})
 	})
 
 	// Assert
 	r.NoError(err)
-	r.Equal("Confirm uninstall current Go version? (y/n): Uninstall aborted by user\n", output)
+	r.Equal("Confirm uninstall Go version go1.21.13? (y/n): Invalid option, please type 'y' or 'n'\nConfirm uninstall Go version go1.21.13? (y/n): Go version \"go1.21.13\" uninstalled successfully!\n", output)
 }
@@ -9,23 +9,32 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func NewUpdateCmd(ctx context.Context, handler handler.UpdateHandler) *cobra.Command {
+func NewUpdateCmd(ctx context.Context, handler handler.UpdateHandler, renderer util.Renderer, defaultStrategy domain.UpdateStrategy) *cobra.Command {
 	var updateStrategyParam domain.UpdateStrategy
+	var unstableParam bool
 
 	updateCmd := &cobra.Command{
 		Use:     "update",
 		Aliases: []string{"update"},
 		Short:   "Update Go version",
-		Long:    "Update Go version to latest major, minor or patch version",
+		Long:    "Update Go version to latest major, minor or patch version. Release candidates and betas are only considered with --unstable",
 		Example: "govm update [patch|minor|major]",
-		Run: func(cmd *cobra.Command, args []string) {
-			v, err := handler.Handle(ctx, &domain.Action{UpdateStrategy: updateStrategyParam})
+		RunE: func(cmd *cobra.Command, args []string) error {
+			update := &domain.Action{UpdateStrategy: updateStrategyParam, Unstable: unstableParam}
+			v, err := handler.Handle(ctx, update)
 			if err != nil {
-				util.PrintError(err.Error())
-				return
+				return err
 			}
-			util.PrintSuccess("Go updated to version \"%s\" successfully!", v)
-			util.PrintWarning("Please, reopen your terminal to start using new version.")
+			renderer.Result(domain.NewInstallResult(*update), func() {
+				util.PrintSuccess("Go updated to version \"%s\" successfully!", v)
+				if update.SignatureUnverified {
+					util.PrintWarning("The signature of the archive could not be verified, see govm.log for details.")
+				}
+				if update.PathUpdated {
+					util.PrintWarning("Please, reopen your terminal to start using new version.")
+				}
+			})
+			return nil
 		},
 	}
 
@@ -33,9 +42,11 @@ func NewUpdateCmd(ctx context.Context, handler handler.UpdateHandler) *cobra.Com
 		(*string)(&updateStrategyParam),
 		"strategy",
 		"s",
-		string(domain.PatchStrategy),
+		string(defaultStrategy),
 		"Update strategy to use (patch, minor, major)",
 	)
 
+	updateCmd.Flags().BoolVar(&unstableParam, "unstable", false, "Consider release candidates and betas too")
+
 	return updateCmd
 }
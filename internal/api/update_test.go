@@ -3,13 +3,17 @@ package api_test
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/api"
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/test"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -17,6 +21,7 @@ type updateCmdSuite struct {
 	suite.Suite
 	ctx     context.Context
 	handler *handler.UpdateHandlerMock
+	render  util.RenderConfig
 	cmd     *cobra.Command
 }
 
@@ -27,7 +32,8 @@ func TestUpdateCmd(t *testing.T) {
 func (r *updateCmdSuite) SetupTest() {
 	r.ctx = context.Background()
 	r.handler = new(handler.UpdateHandlerMock)
-	r.cmd = api.NewUpdateCmd(r.ctx, r.handler)
+	r.render.Format = domain.TextOutput
+	r.cmd = api.NewUpdateCmd(r.ctx, r.handler, util.NewRenderer(&r.render), domain.PatchStrategy)
 }
 
 func (r *updateCmdSuite) TearDownTest() {
@@ -36,28 +42,89 @@ func (r *updateCmdSuite) TearDownTest() {
 
 func (r *updateCmdSuite) TestSuccess() {
 	// Arrange
-	r.handler.On("Handle", r.ctx, &domain.Action{UpdateStrategy: domain.PatchStrategy}).Return("1.15.1", nil)
+	r.handler.On("Handle", r.ctx, &domain.Action{UpdateStrategy: domain.PatchStrategy}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).PathUpdated = true }).
+		Return("1.15.1", nil)
 
 	// Act
 	output, _ := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{"1.15.0"})
-		return nil
+		return r.cmd.RunE(r.cmd, []string{"1.15.0"})
 	})
 
 	// Assert
 	r.Equal("Go updated to version \"1.15.1\" successfully!\nPlease, reopen your terminal to start using new version.\n", output)
 }
 
+func (r *updateCmdSuite) TestSuccessAsYAML() {
+	// Arrange
+	r.render.Format = domain.YAMLOutput
+	r.handler.On("Handle", r.ctx, &domain.Action{UpdateStrategy: domain.PatchStrategy}).
+		Run(func(args mock.Arguments) {
+			update := args.Get(1).(*domain.Action)
+			update.InstalledVersion = "go1.22.3"
+			update.Version = "go1.22.4"
+			update.HomeDir = "/home/user"
+			update.Checksum = "abc123"
+		}).
+		Return("go1.22.4", nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal(strings.Join([]string{
+		"version: go1.22.4\n",
+		"platform: " + domain.HostPlatform().String() + "\n",
+		"path: " + filepath.Join("/home/user", ".govm", "versions", "go1.22.4") + "\n",
+		"checksum: abc123\n",
+		"previous_version: go1.22.3\n",
+		"signature_unverified: false\n",
+		"path_updated: false\n",
+	}, ""), output)
+}
+
+func (r *updateCmdSuite) TestSuccessUnstable() {
+	// Arrange
+	r.NoError(r.cmd.Flags().Set("unstable", "true"))
+	r.handler.On("Handle", r.ctx, &domain.Action{UpdateStrategy: domain.PatchStrategy, Unstable: true}).Return("go1.23rc2", nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{})
+	})
+
+	// Assert
+	r.Equal("Go updated to version \"go1.23rc2\" successfully!\n", output)
+}
+
 func (r *updateCmdSuite) TestErrorHandling() {
 	// Arrange
 	r.handler.On("Handle", r.ctx, &domain.Action{UpdateStrategy: domain.PatchStrategy}).Return("", errors.New("update error"))
 
 	// Act
-	output, _ := test.CaptureOutput(func() error {
-		r.cmd.Run(r.cmd, []string{"1.15.0"})
-		return nil
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"1.15.0"})
+	})
+
+	// Assert
+	r.EqualError(err, "update error")
+	r.Empty(output)
+}
+
+func (r *updateCmdSuite) TestDefaultStrategyFromConfig() {
+	// Arrange
+	cmd := api.NewUpdateCmd(r.ctx, r.handler, util.NewRenderer(&r.render), domain.MinorStrategy)
+	r.handler.On("Handle", r.ctx, &domain.Action{UpdateStrategy: domain.MinorStrategy}).Return("1.16.0", nil)
+	cmd.SetArgs([]string{})
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return cmd.Execute()
 	})
 
 	// Assert
-	r.Equal("update error\n", output)
+	r.NoError(err)
+	r.Equal("Go updated to version \"1.16.0\" successfully!\n", output)
 }
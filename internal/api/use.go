@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func NewUseCmd(ctx context.Context, handler handler.UseHandler) *cobra.Command {
+	return &cobra.Command{
+		Use:     "use",
+		Short:   "Switch to an installed Go version",
+		Long:    "Switch the active Go version to one that is already installed, without downloading it again. When no version is given, the version pinned by the nearest .go-version or go.mod file is used",
+		Example: "govm use [version]",
+		Args:    cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			use := &domain.Action{}
+			if len(args) > 0 {
+				use.Version = args[0]
+			}
+			if err := handler.Handle(ctx, use); err != nil {
+				return err
+			}
+			if use.InstalledVersion == "" {
+				util.PrintSuccess("Now using Go version \"%s\"!", use.Version)
+			} else {
+				util.PrintSuccess("Switched from Go version \"%s\" to \"%s\"!", use.InstalledVersion, use.Version)
+			}
+			if use.PathUpdated {
+				util.PrintWarning("Please, reopen your terminal to start using new version.")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/api"
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type useCmdSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler *handler.UseHandlerMock
+	cmd     *cobra.Command
+}
+
+func TestUseCmd(t *testing.T) {
+	suite.Run(t, new(useCmdSuite))
+}
+
+func (r *useCmdSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = new(handler.UseHandlerMock)
+	r.cmd = api.NewUseCmd(r.ctx, r.handler)
+}
+
+func (r *useCmdSuite) TearDownTest() {
+	r.handler.AssertExpectations(r.T())
+}
+
+func (r *useCmdSuite) TestSuccess() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.22.3"}).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Action).InstalledVersion = "go1.21.0"
+			args.Get(1).(*domain.Action).PathUpdated = true
+		}).
+		Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.22.3"})
+	})
+
+	// Assert
+	r.Equal("Switched from Go version \"go1.21.0\" to \"go1.22.3\"!\nPlease, reopen your terminal to start using new version.\n", output)
+}
+
+func (r *useCmdSuite) TestSuccessWithoutPreviousVersion() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.22.3"}).Return(nil)
+
+	// Act
+	output, _ := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.22.3"})
+	})
+
+	// Assert
+	r.Equal("Now using Go version \"go1.22.3\"!\n", output)
+}
+
+func (r *useCmdSuite) TestErrorHandling() {
+	// Arrange
+	r.handler.On("Handle", r.ctx, &domain.Action{Version: "go1.22.3"}).Return(errors.New("use error"))
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.cmd.RunE(r.cmd, []string{"go1.22.3"})
+	})
+
+	// Assert
+	r.EqualError(err, "use error")
+	r.Empty(output)
+}
+
+func (r *useCmdSuite) TestInvalidArguments() {
+	// Arrange
+	r.cmd.SetArgs([]string{"go1.22.3", "go1.21.0"})
+
+	// Act
+	err := r.cmd.Execute()
+
+	// Assert
+	r.Error(err)
+	r.EqualError(err, "accepts at most 1 arg(s), received 2")
+}
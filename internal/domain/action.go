@@ -2,9 +2,7 @@ package domain
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 )
 
@@ -12,53 +10,222 @@ type UpdateStrategy string
 
 const (
 	exportBegin  = "# The next lines are added by govm"
-	exportGoRoot = "export GOROOT=%s"
 	exportGoPath = "export GOPATH=$HOME/go"
-	exportPath   = "export PATH=$PATH:%s"
+	exportPath   = "export PATH=%s:$PATH"
 	exportEnd    = "# End of govm path"
 
+	// The block added by govm before versions were kept side by side, when the one
+	// version installed lived in ~/.govm/go.
+	legacyExportGoRoot = "export GOROOT=%s"
+	legacyExportPath   = "export PATH=$PATH:%s"
+
 	MajorStrategy UpdateStrategy = "major"
 	MinorStrategy UpdateStrategy = "minor"
 	PatchStrategy UpdateStrategy = "patch"
 )
 
+var Shims = []string{"go", "gofmt"}
+
+// GoSourceRepo is the repository Go is built from, unless another one is given.
+const GoSourceRepo = "https://go.googlesource.com/go"
+
+// TipRef is the ref built by "govm install tip", the head of the repository.
+const TipRef = "HEAD"
+
 type Action struct {
 	Version          string
 	HomeDir          string
+	Root             string
 	InstalledVersion string
 	UpdateStrategy   UpdateStrategy
+	PathUpdated      bool
+	Checksum         string
+	Cached           bool
+	// Extracted is set when the archive was extracted into the staging directory as it
+	// was downloaded, which leaves no archive to verify, cache or extract.
+	Extracted bool
+	// SignatureUnverified is set when the archive signature couldn't be verified and
+	// the signature policy only warns about it.
+	SignatureUnverified bool
+	PreviousCurrent     string
+	RunCommands         map[string][]byte
+	// Archive is the archive to install instead of the one of the release index, as
+	// given with --from-file or downloaded from ArchiveURL.
+	Archive string
+	// ArchiveURL is the URL given with --from-url.
+	ArchiveURL string
+	// Unstable includes release candidates and betas, as asked with --unstable.
+	Unstable bool
+	// Source is the git ref to build Go from, as given with --source, or TipRef.
+	Source string
+	// SourceRepo is the repository, or local checkout, Source is fetched from.
+	SourceRepo string
+	// Bootstrap is the installed version Source is built with.
+	Bootstrap string
+	// OS and Arch select a toolchain for another platform, as given with --os and
+	// --arch. Either one defaults to the host's.
+	OS   string
+	Arch string
+}
+
+// Target is the platform of the toolchain the action is about.
+func (r Action) Target() Platform {
+	target := HostPlatform()
+	if r.OS != "" {
+		target.OS = r.OS
+	}
+	if r.Arch != "" {
+		target.Arch = r.Arch
+	}
+	return target
 }
 
 func (r Action) Filename() string {
-	return fmt.Sprintf("%s.%s-%s.tar.gz", r.Version, runtime.GOOS, runtime.GOARCH)
+	target := r.Target()
+	return fmt.Sprintf("%s.%s-%s.tar.gz", r.Version, target.OS, target.Arch)
 }
 
+// DownloadFile keeps a partial download between runs, so it can be resumed.
 func (r Action) DownloadFile() string {
-	return filepath.Join(os.TempDir(), r.Filename())
+	return filepath.Join(r.CacheDir(), r.Filename()+".part")
+}
+
+// URLDownloadFile is where the archive of ArchiveURL is downloaded to.
+func (r Action) URLDownloadFile() string {
+	return filepath.Join(r.CacheDir(), "url-download.tar.gz.part")
+}
+
+// FromArchive reports whether the action installs an archive given by the user
+// rather than a version of the release index.
+func (r Action) FromArchive() bool {
+	return r.Archive != "" || r.ArchiveURL != ""
+}
+
+// FromSource reports whether the action builds Go from a git ref rather than
+// installing a release.
+func (r Action) FromSource() bool {
+	return r.Source != ""
+}
+
+// SourceVersion is the version a build of ref is installed as: "gotip" for TipRef
+// and "gotip-<ref>" for any other ref, e.g. "gotip-release-branch.go1.23".
+func SourceVersion(ref string) string {
+	if ref == TipRef {
+		return "gotip"
+	}
+	return "gotip-" + strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_' {
+			return c
+		}
+		return '-'
+	}, ref)
 }
 
+// HomeGovmDir is where versions, shims and the cache are kept: the configured
+// root, or ~/.govm by default.
 func (r Action) HomeGovmDir() string {
+	if r.Root != "" {
+		return r.Root
+	}
 	return filepath.Join(r.HomeDir, ".govm")
 }
 
-func (r Action) HomeGoDir() string {
-	return filepath.Join(r.HomeGovmDir(), "go")
+// HomeVersionsDir is where the versions of the target are installed. Toolchains for
+// other platforms are kept apart, under targets/<os>-<arch>, so they never take the
+// place of the host's.
+func (r Action) HomeVersionsDir() string {
+	if target := r.Target(); !target.IsHost() {
+		return filepath.Join(r.HomeGovmDir(), "targets", target.OS+"-"+target.Arch)
+	}
+	return filepath.Join(r.HomeGovmDir(), "versions")
+}
+
+func (r Action) HomeVersionDir() string {
+	return filepath.Join(r.HomeVersionsDir(), r.Version)
+}
+
+// HomeVersionStagingDir is where a version is extracted before replacing the installed
+// one. It sits next to HomeVersionDir so it can be renamed into place atomically.
+func (r Action) HomeVersionStagingDir() string {
+	return filepath.Join(r.HomeVersionsDir(), "."+r.Version+".staging")
+}
+
+// HomeVersionBackupDir keeps the installed version aside until the action succeeds.
+func (r Action) HomeVersionBackupDir() string {
+	return filepath.Join(r.HomeVersionsDir(), "."+r.Version+".backup")
+}
+
+func (r Action) HomeVersionBinDir() string {
+	return filepath.Join(r.HomeVersionDir(), "bin")
+}
+
+func (r Action) HomeCurrentDir() string {
+	return filepath.Join(r.HomeGovmDir(), "current")
+}
+
+// ConfigFile always lives in ~/.govm, since the root itself is configurable.
+func (r Action) ConfigFile() string {
+	return filepath.Join(r.HomeDir, ".govm", "config.toml")
+}
+
+func (r Action) HomeShimsDir() string {
+	return filepath.Join(r.HomeGovmDir(), "shims")
+}
+
+func (r Action) CacheDir() string {
+	return filepath.Join(r.HomeGovmDir(), "cache")
+}
+
+func (r Action) CacheIndexFile() string {
+	return filepath.Join(r.CacheDir(), "index.json")
+}
+
+func (r Action) CacheArchiveDir() string {
+	return filepath.Join(r.CacheDir(), r.Checksum)
 }
 
-func (r Action) HomeGoBinDir() string {
-	return filepath.Join(r.HomeGoDir(), "bin")
+func (r Action) CacheFile() string {
+	return filepath.Join(r.CacheArchiveDir(), r.Filename())
 }
 
 func (r Action) Export() string {
 	return strings.Join([]string{
 		exportBegin,
-		fmt.Sprintf(exportGoRoot, r.HomeGoDir()),
 		exportGoPath,
-		fmt.Sprintf(exportPath, r.HomeGoBinDir()),
+		fmt.Sprintf(exportPath, r.HomeShimsDir()),
 		exportEnd,
 	}, "\n")
 }
 
+// LegacyHomeGoDir is where the one installed version was kept by earlier releases of
+// govm, always under ~/.govm.
+func (r Action) LegacyHomeGoDir() string {
+	return filepath.Join(r.HomeDir, ".govm", "go")
+}
+
+// LegacyExport is the block earlier releases of govm added to the shell rc files,
+// removed whenever the rc files are changed.
+func (r Action) LegacyExport() string {
+	return strings.Join([]string{
+		exportBegin,
+		fmt.Sprintf(legacyExportGoRoot, r.LegacyHomeGoDir()),
+		exportGoPath,
+		fmt.Sprintf(legacyExportPath, filepath.Join(r.LegacyHomeGoDir(), "bin")),
+		exportEnd,
+	}, "\n")
+}
+
+// SaveRunCommand keeps the content of a shell rc file before its first change, so it
+// can be restored if the action fails.
+func (r *Action) SaveRunCommand(path string, content []byte) {
+	if r.RunCommands == nil {
+		r.RunCommands = make(map[string][]byte)
+	}
+	if _, exists := r.RunCommands[path]; !exists {
+		r.RunCommands[path] = content
+	}
+}
+
 func (r *Action) CheckUpdateStrategy() error {
 	switch r.UpdateStrategy {
 	case MajorStrategy, MinorStrategy, PatchStrategy:
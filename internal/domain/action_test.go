@@ -2,7 +2,6 @@ package domain_test
 
 import (
 	"fmt"
-	"os"
 	"path"
 	"runtime"
 	"testing"
@@ -16,20 +15,35 @@ func TestAction(t *testing.T) {
 		Version:        "go1.19.13",
 		HomeDir:        "/home/user",
 		UpdateStrategy: domain.MinorStrategy,
+		Checksum:       "abc123",
 	}
 
 	filename := fmt.Sprintf("go1.19.13.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
 
 	assert.Equal(t, filename, action.Filename())
-	assert.Equal(t, path.Join(os.TempDir(), filename), action.DownloadFile())
+	assert.Equal(t, path.Join("/home/user/.govm/cache", filename+".part"), action.DownloadFile())
+	assert.Equal(t, path.Join("/home/user/.govm/cache/abc123", filename), action.CacheFile())
+	assert.Equal(t, "/home/user/.govm/cache/abc123", action.CacheArchiveDir())
+	assert.Equal(t, "/home/user/.govm/cache/index.json", action.CacheIndexFile())
+	assert.Equal(t, "/home/user/.govm/cache", action.CacheDir())
+	assert.Equal(t, "/home/user/.govm/cache/url-download.tar.gz.part", action.URLDownloadFile())
 
-	assert.Equal(t, "/home/user/.govm/go/bin", action.HomeGoBinDir())
-	assert.Equal(t, "/home/user/.govm/go", action.HomeGoDir())
+	assert.Equal(t, "/home/user/.govm/config.toml", action.ConfigFile())
+	assert.Equal(t, "/home/user/.govm/shims", action.HomeShimsDir())
+	assert.Equal(t, "/home/user/.govm/current", action.HomeCurrentDir())
+	assert.Equal(t, "/home/user/.govm/versions/go1.19.13/bin", action.HomeVersionBinDir())
+	assert.Equal(t, "/home/user/.govm/versions/go1.19.13", action.HomeVersionDir())
+	assert.Equal(t, "/home/user/.govm/versions/.go1.19.13.staging", action.HomeVersionStagingDir())
+	assert.Equal(t, "/home/user/.govm/versions/.go1.19.13.backup", action.HomeVersionBackupDir())
+	assert.Equal(t, "/home/user/.govm/versions", action.HomeVersionsDir())
 	assert.Equal(t, "/home/user/.govm", action.HomeGovmDir())
 
-	assert.Equal(t, "# The next lines are added by govm\nexport GOROOT=/home/user/.govm/go\nexport GOPATH=$HOME/go\nexport PATH=$PATH:/home/user/.govm/go/bin\n# End of govm path", action.Export())
+	assert.Equal(t, "# The next lines are added by govm\nexport GOPATH=$HOME/go\nexport PATH=/home/user/.govm/shims:$PATH\n# End of govm path", action.Export())
+	assert.Equal(t, "/home/user/.govm/go", action.LegacyHomeGoDir())
+	assert.Equal(t, "# The next lines are added by govm\nexport GOROOT=/home/user/.govm/go\nexport GOPATH=$HOME/go\nexport PATH=$PATH:/home/user/.govm/go/bin\n# End of govm path", action.LegacyExport())
 	assert.Equal(t, domain.MinorStrategy, action.UpdateStrategy)
 	assert.NoError(t, action.CheckUpdateStrategy())
+	assert.False(t, action.FromArchive())
 }
 
 func TestAction_UpdateStrategyError(t *testing.T) {
@@ -41,3 +55,71 @@ func TestAction_UpdateStrategyError(t *testing.T) {
 
 	assert.Error(t, action.CheckUpdateStrategy())
 }
+
+func TestActionWithRoot(t *testing.T) {
+	action := domain.Action{
+		Version: "go1.19.13",
+		HomeDir: "/home/user",
+		Root:    "/opt/govm",
+	}
+
+	assert.Equal(t, "/opt/govm", action.HomeGovmDir())
+	assert.Equal(t, "/opt/govm/versions/go1.19.13", action.HomeVersionDir())
+	assert.Equal(t, "/opt/govm/shims", action.HomeShimsDir())
+	assert.Equal(t, "/opt/govm/cache", action.CacheDir())
+	assert.Equal(t, "/home/user/.govm/config.toml", action.ConfigFile())
+}
+
+func TestActionSaveRunCommand(t *testing.T) {
+	action := domain.Action{}
+
+	action.SaveRunCommand("/home/user/.bashrc", []byte("original"))
+	action.SaveRunCommand("/home/user/.bashrc", []byte("changed"))
+
+	assert.Equal(t, map[string][]byte{"/home/user/.bashrc": []byte("original")}, action.RunCommands)
+}
+
+func TestActionFromArchive(t *testing.T) {
+	assert.True(t, domain.Action{Archive: "/tmp/go1.22.3.linux-amd64.tar.gz"}.FromArchive())
+	assert.True(t, domain.Action{ArchiveURL: "https://example.com/go1.22.3.linux-amd64.tar.gz"}.FromArchive())
+}
+
+func TestActionFromSource(t *testing.T) {
+	assert.True(t, domain.Action{Source: domain.TipRef}.FromSource())
+	assert.False(t, domain.Action{Version: "go1.22.3"}.FromSource())
+}
+
+func TestSourceVersion(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{ref: domain.TipRef, expected: "gotip"},
+		{ref: "master", expected: "gotip-master"},
+		{ref: "release-branch.go1.23", expected: "gotip-release-branch.go1.23"},
+		{ref: "refs/changes/12/34/5", expected: "gotip-refs-changes-12-34-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.SourceVersion(tt.ref))
+		})
+	}
+}
+
+func TestActionTarget(t *testing.T) {
+	action := domain.Action{Version: "go1.22.3", HomeDir: "/home/user", OS: "plan9", Arch: "arm"}
+
+	assert.Equal(t, domain.Platform{OS: "plan9", Arch: "arm"}, action.Target())
+	assert.Equal(t, "go1.22.3.plan9-arm.tar.gz", action.Filename())
+	assert.Equal(t, "/home/user/.govm/targets/plan9-arm", action.HomeVersionsDir())
+	assert.Equal(t, "/home/user/.govm/targets/plan9-arm/go1.22.3", action.HomeVersionDir())
+	assert.Equal(t, "/home/user/.govm/targets/plan9-arm/.go1.22.3.staging", action.HomeVersionStagingDir())
+
+	action = domain.Action{Version: "go1.22.3", HomeDir: "/home/user", Arch: "arm"}
+	assert.Equal(t, domain.Platform{OS: runtime.GOOS, Arch: "arm"}, action.Target())
+
+	action = domain.Action{Version: "go1.22.3", HomeDir: "/home/user", OS: runtime.GOOS, Arch: runtime.GOARCH}
+	assert.True(t, action.Target().IsHost())
+	assert.Equal(t, "/home/user/.govm/versions", action.HomeVersionsDir())
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type CachedArchive struct {
+	Checksum string
+	Filename string
+	Size     int64
+	ModTime  time.Time
+}
+
+// String formats the archive as a row of "govm cache list".
+func (r CachedArchive) String() string {
+	return fmt.Sprintf("%-36s %10s  %s  %s", r.Filename, r.SizeString(), r.ModTime.Format(time.DateTime), r.Checksum)
+}
+
+func (r CachedArchive) SizeString() string {
+	return FormatSize(r.Size)
+}
+
+// FormatSize renders a byte count using binary units, e.g. "64.2 MiB".
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
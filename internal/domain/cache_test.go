@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedArchive(t *testing.T) {
+	archive := domain.CachedArchive{
+		Checksum: "abc123",
+		Filename: "go1.22.3.linux-amd64.tar.gz",
+		Size:     68_958_123,
+		ModTime:  time.Date(2024, 5, 7, 10, 30, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, "65.8 MiB", archive.SizeString())
+	assert.Equal(t, "go1.22.3.linux-amd64.tar.gz            65.8 MiB  2024-05-07 10:30:00  abc123", archive.String())
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{size: 0, expected: "0 B"},
+		{size: 1023, expected: "1023 B"},
+		{size: 1024, expected: "1.0 KiB"},
+		{size: 1536, expected: "1.5 KiB"},
+		{size: 5 * 1024 * 1024 * 1024, expected: "5.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.FormatSize(tt.size))
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type ShellIntegration string
+
+type CachePolicy string
+
+type ColorMode string
+
+type SignaturePolicy string
+
+const (
+	DefaultMirror   = "https://go.dev/dl"
+	DefaultIndexTTL = time.Hour
+
+	MirrorEnv   = "GOVM_MIRROR"
+	IndexURLEnv = "GOVM_INDEX_URL"
+
+	indexQuery = "/?mode=json&include=all"
+
+	ConfigMirror           = "mirror"
+	ConfigIndexURL         = "index_url"
+	ConfigRoot             = "root"
+	ConfigUpdateStrategy   = "update_strategy"
+	ConfigShellIntegration = "shell_integration"
+	ConfigCache            = "cache"
+	ConfigIndexTTL         = "index_ttl"
+	ConfigColor            = "color"
+	ConfigSignature        = "signature"
+
+	// RcShellIntegration adds the shims directory to PATH in the shell rc files.
+	RcShellIntegration ShellIntegration = "rc"
+	// NoShellIntegration leaves the shell rc files alone, e.g. when using "govm hook".
+	NoShellIntegration ShellIntegration = "none"
+
+	KeepCachePolicy CachePolicy = "keep"
+	OffCachePolicy  CachePolicy = "off"
+
+	AutoColor   ColorMode = "auto"
+	AlwaysColor ColorMode = "always"
+	NeverColor  ColorMode = "never"
+
+	// OffSignaturePolicy trusts the checksum published in the release index.
+	OffSignaturePolicy SignaturePolicy = "off"
+	// WarnSignaturePolicy verifies the archive signature, warning when it can't be verified.
+	WarnSignaturePolicy SignaturePolicy = "warn"
+	// RequireSignaturePolicy refuses archives whose signature can't be verified.
+	RequireSignaturePolicy SignaturePolicy = "require"
+)
+
+// Config holds the settings read from ~/.govm/config.toml, which can be
+// overridden by environment variables. Empty fields take their default value.
+type Config struct {
+	// Mirror is the base URL the Go archives are downloaded from, e.g.
+	// https://golang.google.cn/dl.
+	Mirror string `toml:"mirror,omitempty"`
+	// IndexURL is the URL of the release index. When empty it is derived from Mirror.
+	IndexURL string `toml:"index_url,omitempty"`
+	// Root is the directory versions, shims and the cache are kept in, ~/.govm by default.
+	Root             string           `toml:"root,omitempty"`
+	UpdateStrategy   UpdateStrategy   `toml:"update_strategy,omitempty"`
+	ShellIntegration ShellIntegration `toml:"shell_integration,omitempty"`
+	Cache            CachePolicy      `toml:"cache,omitempty"`
+	IndexTTL         string           `toml:"index_ttl,omitempty"`
+	Color            ColorMode        `toml:"color,omitempty"`
+	Signature        SignaturePolicy  `toml:"signature,omitempty"`
+}
+
+// ConfigKeys lists the keys accepted by Get and Set, in display order.
+func ConfigKeys() []string {
+	return []string{
+		ConfigMirror,
+		ConfigIndexURL,
+		ConfigRoot,
+		ConfigUpdateStrategy,
+		ConfigShellIntegration,
+		ConfigCache,
+		ConfigIndexTTL,
+		ConfigColor,
+		ConfigSignature,
+	}
+}
+
+// ApplyEnv overrides the settings with the ones found in the environment.
+func (r *Config) ApplyEnv(getenv func(string) string) {
+	if v := getenv(MirrorEnv); v != "" {
+		r.Mirror = v
+	}
+	if v := getenv(IndexURLEnv); v != "" {
+		r.IndexURL = v
+	}
+}
+
+func (r Config) MirrorURL() string {
+	if r.Mirror == "" {
+		return DefaultMirror
+	}
+	return strings.TrimSuffix(r.Mirror, "/")
+}
+
+// DownloadURL is the format of an archive URL, to be completed with Action.Filename.
+func (r Config) DownloadURL() string {
+	return r.MirrorURL() + "/%s"
+}
+
+func (r Config) ReleaseIndexURL() string {
+	if r.IndexURL == "" {
+		return r.MirrorURL() + indexQuery
+	}
+	return r.IndexURL
+}
+
+func (r Config) Strategy() UpdateStrategy {
+	if r.UpdateStrategy == "" {
+		return PatchStrategy
+	}
+	return r.UpdateStrategy
+}
+
+func (r Config) Shell() ShellIntegration {
+	if r.ShellIntegration == "" {
+		return RcShellIntegration
+	}
+	return r.ShellIntegration
+}
+
+func (r Config) CachePolicy() CachePolicy {
+	if r.Cache == "" {
+		return KeepCachePolicy
+	}
+	return r.Cache
+}
+
+func (r Config) IndexCacheTTL() time.Duration {
+	if d, err := time.ParseDuration(r.IndexTTL); err == nil {
+		return d
+	}
+	return DefaultIndexTTL
+}
+
+func (r Config) ColorMode() ColorMode {
+	if r.Color == "" {
+		return AutoColor
+	}
+	return r.Color
+}
+
+func (r Config) SignaturePolicy() SignaturePolicy {
+	if r.Signature == "" {
+		return OffSignaturePolicy
+	}
+	return r.Signature
+}
+
+// Get returns the value in effect for key, defaults included.
+func (r Config) Get(key string) (string, error) {
+	switch key {
+	case ConfigMirror:
+		return r.MirrorURL(), nil
+	case ConfigIndexURL:
+		return r.ReleaseIndexURL(), nil
+	case ConfigRoot:
+		if r.Root == "" {
+			return filepath.Join("~", ".govm"), nil
+		}
+		return r.Root, nil
+	case ConfigUpdateStrategy:
+		return string(r.Strategy()), nil
+	case ConfigShellIntegration:
+		return string(r.Shell()), nil
+	case ConfigCache:
+		return string(r.CachePolicy()), nil
+	case ConfigIndexTTL:
+		return r.IndexCacheTTL().String(), nil
+	case ConfigColor:
+		return string(r.ColorMode()), nil
+	case ConfigSignature:
+		return string(r.SignaturePolicy()), nil
+	default:
+		return "", NewInvalidConfigKeyError(key)
+	}
+}
+
+// Set validates and stores value for key. An empty value restores the default.
+func (r *Config) Set(key, value string) error {
+	if err := validateConfigValue(key, value); err != nil {
+		return err
+	}
+	return r.set(key, value)
+}
+
+// Validate checks the value of every key, e.g. of a config file edited by hand.
+func (r Config) Validate() error {
+	for _, key := range ConfigKeys() {
+		if err := validateConfigValue(key, r.value(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConfigValue(key, value string) error {
+	var valid bool
+	switch key {
+	case ConfigMirror, ConfigIndexURL:
+		u, err := url.Parse(value)
+		valid = err == nil && u.Scheme != "" && u.Host != ""
+	case ConfigRoot:
+		valid = filepath.IsAbs(value)
+	case ConfigUpdateStrategy:
+		valid = (&Action{UpdateStrategy: UpdateStrategy(value)}).CheckUpdateStrategy() == nil
+	case ConfigShellIntegration:
+		valid = value == string(RcShellIntegration) || value == string(NoShellIntegration)
+	case ConfigCache:
+		valid = value == string(KeepCachePolicy) || value == string(OffCachePolicy)
+	case ConfigIndexTTL:
+		d, err := time.ParseDuration(value)
+		valid = err == nil && d >= 0
+	case ConfigColor:
+		valid = value == string(AutoColor) || value == string(AlwaysColor) || value == string(NeverColor)
+	case ConfigSignature:
+		valid = value == string(OffSignaturePolicy) || value == string(WarnSignaturePolicy) || value == string(RequireSignaturePolicy)
+	default:
+		return NewInvalidConfigKeyError(key)
+	}
+
+	// An empty value is the default.
+	if value != "" && !valid {
+		return NewInvalidConfigValueError(key, value)
+	}
+	return nil
+}
+
+// value returns the value stored for key, empty when it takes the default.
+func (r Config) value(key string) string {
+	switch key {
+	case ConfigMirror:
+		return r.Mirror
+	case ConfigIndexURL:
+		return r.IndexURL
+	case ConfigRoot:
+		return r.Root
+	case ConfigUpdateStrategy:
+		return string(r.UpdateStrategy)
+	case ConfigShellIntegration:
+		return string(r.ShellIntegration)
+	case ConfigCache:
+		return string(r.Cache)
+	case ConfigIndexTTL:
+		return r.IndexTTL
+	case ConfigColor:
+		return string(r.Color)
+	case ConfigSignature:
+		return string(r.Signature)
+	default:
+		return ""
+	}
+}
+
+func (r *Config) set(key, value string) error {
+	switch key {
+	case ConfigMirror:
+		r.Mirror = value
+	case ConfigIndexURL:
+		r.IndexURL = value
+	case ConfigRoot:
+		r.Root = value
+	case ConfigUpdateStrategy:
+		r.UpdateStrategy = UpdateStrategy(value)
+	case ConfigShellIntegration:
+		r.ShellIntegration = ShellIntegration(value)
+	case ConfigCache:
+		r.Cache = CachePolicy(value)
+	case ConfigIndexTTL:
+		r.IndexTTL = value
+	case ConfigColor:
+		r.Color = ColorMode(value)
+	case ConfigSignature:
+		r.Signature = SignaturePolicy(value)
+	default:
+		return NewInvalidConfigKeyError(key)
+	}
+	return nil
+}
+
+// String formats the config as the "key = value" lines of "govm config list".
+func (r Config) String() string {
+	lines := make([]string, 0, len(ConfigKeys()))
+	for _, key := range ConfigKeys() {
+		value, _ := r.Get(key)
+		lines = append(lines, fmt.Sprintf("%s = %s", key, value))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,187 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	config := domain.Config{}
+
+	assert.Equal(t, "https://go.dev/dl", config.MirrorURL())
+	assert.Equal(t, "https://go.dev/dl/%s", config.DownloadURL())
+	assert.Equal(t, "https://go.dev/dl/?mode=json&include=all", config.ReleaseIndexURL())
+}
+
+func TestConfigMirror(t *testing.T) {
+	config := domain.Config{Mirror: "https://golang.google.cn/dl/"}
+
+	assert.Equal(t, "https://golang.google.cn/dl", config.MirrorURL())
+	assert.Equal(t, "https://golang.google.cn/dl/%s", config.DownloadURL())
+	assert.Equal(t, "https://golang.google.cn/dl/?mode=json&include=all", config.ReleaseIndexURL())
+}
+
+func TestConfigIndexURL(t *testing.T) {
+	config := domain.Config{
+		Mirror:   "https://artifactory.example.com/go-dl",
+		IndexURL: "https://artifactory.example.com/go-index/releases.json",
+	}
+
+	assert.Equal(t, "https://artifactory.example.com/go-dl/%s", config.DownloadURL())
+	assert.Equal(t, "https://artifactory.example.com/go-index/releases.json", config.ReleaseIndexURL())
+}
+
+func TestConfigApplyEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected domain.Config
+	}{
+		{
+			name:     "No Env",
+			env:      map[string]string{},
+			expected: domain.Config{Mirror: "https://file.example.com", IndexURL: "https://file.example.com/index"},
+		},
+		{
+			name:     "Mirror Env",
+			env:      map[string]string{"GOVM_MIRROR": "https://env.example.com"},
+			expected: domain.Config{Mirror: "https://env.example.com", IndexURL: "https://file.example.com/index"},
+		},
+		{
+			name: "Both Env",
+			env: map[string]string{
+				"GOVM_MIRROR":    "https://env.example.com",
+				"GOVM_INDEX_URL": "https://env.example.com/index",
+			},
+			expected: domain.Config{Mirror: "https://env.example.com", IndexURL: "https://env.example.com/index"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := domain.Config{Mirror: "https://file.example.com", IndexURL: "https://file.example.com/index"}
+
+			config.ApplyEnv(func(key string) string { return tt.env[key] })
+
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
+
+func TestConfigGetDefaults(t *testing.T) {
+	config := domain.Config{}
+
+	expected := strings.Join([]string{
+		"mirror = https://go.dev/dl",
+		"index_url = https://go.dev/dl/?mode=json&include=all",
+		"root = ~/.govm",
+		"update_strategy = patch",
+		"shell_integration = rc",
+		"cache = keep",
+		"index_ttl = 1h0m0s",
+		"color = auto",
+		"signature = off",
+	}, "\n")
+
+	assert.Equal(t, expected, config.String())
+	assert.Equal(t, domain.PatchStrategy, config.Strategy())
+	assert.Equal(t, domain.RcShellIntegration, config.Shell())
+	assert.Equal(t, domain.KeepCachePolicy, config.CachePolicy())
+	assert.Equal(t, time.Hour, config.IndexCacheTTL())
+	assert.Equal(t, domain.AutoColor, config.ColorMode())
+	assert.Equal(t, domain.OffSignaturePolicy, config.SignaturePolicy())
+}
+
+func TestConfigSet(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		expected string
+	}{
+		{key: "mirror", value: "https://golang.google.cn/dl", expected: "https://golang.google.cn/dl"},
+		{key: "index_url", value: "https://example.com/index.json", expected: "https://example.com/index.json"},
+		{key: "root", value: "/opt/govm", expected: "/opt/govm"},
+		{key: "update_strategy", value: "minor", expected: "minor"},
+		{key: "shell_integration", value: "none", expected: "none"},
+		{key: "cache", value: "off", expected: "off"},
+		{key: "index_ttl", value: "30m", expected: "30m0s"},
+		{key: "color", value: "never", expected: "never"},
+		{key: "signature", value: "require", expected: "require"},
+		{key: "update_strategy", value: "", expected: "patch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			config := domain.Config{}
+
+			err := config.Set(tt.key, tt.value)
+			actual, _ := config.Get(tt.key)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestConfigSetInvalidValue(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{key: "mirror", value: "not a url"},
+		{key: "index_url", value: "/relative/index.json"},
+		{key: "root", value: "relative/root"},
+		{key: "update_strategy", value: "latest"},
+		{key: "shell_integration", value: "profile"},
+		{key: "cache", value: "forever"},
+		{key: "index_ttl", value: "-1h"},
+		{key: "color", value: "rainbow"},
+		{key: "signature", value: "strict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			config := domain.Config{}
+
+			err := config.Set(tt.key, tt.value)
+
+			assert.Equal(t, domain.NewInvalidConfigValueError(tt.key, tt.value), err)
+			assert.Equal(t, domain.Config{}, config)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   domain.Config
+		expected error
+	}{
+		{name: "Defaults", config: domain.Config{}, expected: nil},
+		{name: "Valid", config: domain.Config{Root: "/opt/govm", UpdateStrategy: domain.MinorStrategy, Signature: domain.RequireSignaturePolicy}, expected: nil},
+		{name: "Relative Root", config: domain.Config{Root: "govm"}, expected: domain.NewInvalidConfigValueError("root", "govm")},
+		{name: "Invalid Update Strategy", config: domain.Config{UpdateStrategy: "minro"}, expected: domain.NewInvalidConfigValueError("update_strategy", "minro")},
+		{name: "Invalid Signature", config: domain.Config{Signature: "requires"}, expected: domain.NewInvalidConfigValueError("signature", "requires")},
+		{name: "Invalid Index TTL", config: domain.Config{IndexTTL: "1 hour"}, expected: domain.NewInvalidConfigValueError("index_ttl", "1 hour")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.config.Validate())
+		})
+	}
+}
+
+func TestConfigInvalidKey(t *testing.T) {
+	config := domain.Config{}
+
+	_, errGet := config.Get("proxy")
+	errSet := config.Set("proxy", "http://proxy")
+
+	assert.Equal(t, domain.NewInvalidConfigKeyError("proxy"), errGet)
+	assert.Equal(t, domain.NewInvalidConfigKeyError("proxy"), errSet)
+}
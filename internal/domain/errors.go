@@ -1,17 +1,40 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
 )
 
 const (
 	errMessageUnexpected             = "an unexpected error occurred, please verify govm.log for more information"
 	errMessageVersionNotAvailable    = "go version \"%s\" is not available"
+	errMessageVersionNotAvailableFor = "go version \"%s\" is not available for %s"
 	errMessageNoUpdatesAvailable     = "no %s updates available for version \"%s\""
 	errMessageNoGoInstallationsFound = "no go installations found"
 	errMessageInvalidUpdateStrategy  = "\"%s\" is not a valid update strategy"
-
-	ErrCodeListVersions = 1
+	errMessageNoPinnedVersionFound   = "no go version specified and no .go-version or go.mod found in \"%s\" or its parents"
+	errMessageInvalidShell           = "\"%s\" is not a supported shell, use one of: bash, zsh, fish"
+	errMessageNoActiveVersion        = "no go version selected, run \"govm use [version]\" first"
+	errMessageVersionNotInstalled    = "go version \"%s\" is not installed, run \"govm install %s\" first"
+	errMessageInvalidCacheKeep       = "%d is not a valid number of archives to keep"
+	errMessageNotAvailableOffline    = "%s is not available offline, run the command again without --offline"
+	errMessageInvalidConfigKey       = "\"%s\" is not a valid config key, use one of: %s"
+	errMessageInvalidConfigValue     = "\"%s\" is not a valid value for %s"
+	errMessageCancelled              = "operation cancelled, changes made so far were rolled back"
+	errMessageInvalidArchive         = "\"%s\" is not a valid go archive"
+	errMessageArchivePlatform        = "\"%s\" is a go archive for %s, not for %s"
+	errMessageSignatureNotVerified   = "the signature of \"%s\" could not be verified"
+	errMessageUnstableVersion        = "go version \"%s\" is not a stable release, use --unstable to install it"
+	errMessageNoBootstrapVersion     = "building go from source needs an installed go version to bootstrap from, run \"govm install [version]\" first"
+	errMessageSourceFetch            = "\"%s\" could not be fetched from \"%s\""
+	errMessageSourceBuild            = "building \"%s\" failed, its output is in govm.log"
+	errMessageInvalidOutputFormat    = "\"%s\" is not a valid output format, use one of: %s"
+	errMessageAborted                = "aborted by user"
+	errMessageConfirmationRequired   = "stdin is not a terminal, pass --yes to %s without confirmation"
 
 	ErrCodeCheckUserHome               = 1
 	ErrCodeCheckVersion                = 2
@@ -34,48 +57,382 @@ const (
 	ErrCodeRemoveFromPathStat          = 19
 	ErrCodeRemoveFromPathRead          = 20
 	ErrCodeRemoveFromPathWrite         = 21
+	ErrCodeSetCurrentVersion           = 22
+	ErrCodeListLocalVersions           = 23
+	ErrCodeGetCurrentVersion           = 24
+	ErrCodeCheckLocalVersion           = 25
+	ErrCodeResolveWorkingDir           = 26
+	ErrCodeResolveReadFile             = 27
+	ErrCodeCreateShims                 = 28
+	ErrCodeGetExecutable               = 29
+	ErrCodeRunCommand                  = 30
+	ErrCodeDownloadCreateDir           = 31
+	ErrCodeCacheCreateDir              = 32
+	ErrCodeCacheStore                  = 33
+	ErrCodeCacheList                   = 34
+	ErrCodeCacheRemove                 = 35
+	ErrCodeReadConfig                  = 36
+	ErrCodeWriteConfig                 = 37
+	ErrCodeInstallVersion              = 38
+	ErrCodeRollback                    = 39
+	ErrCodeSourceCreateDir             = 40
+	ErrCodeListVersions                = 41
+
+	// Errors meant for the user have codes of their own, from 100 on.
+	ErrCodeVersionNotAvailable    = 100
+	ErrCodeVersionNotAvailableFor = 101
+	ErrCodeNoUpdatesAvailable     = 102
+	ErrCodeNoGoInstallationsFound = 103
+	ErrCodeInvalidUpdateStrategy  = 104
+	ErrCodeVersionNotInstalled    = 105
+	ErrCodeNoPinnedVersionFound   = 106
+	ErrCodeInvalidShell           = 107
+	ErrCodeNoActiveVersion        = 108
+	ErrCodeInvalidCacheKeep       = 109
+	ErrCodeNotAvailableOffline    = 110
+	ErrCodeInvalidConfigKey       = 111
+	ErrCodeInvalidConfigValue     = 112
+	ErrCodeCancelled              = 113
+	ErrCodeSignatureNotVerified   = 114
+	ErrCodeInvalidArchive         = 115
+	ErrCodeUnstableVersion        = 116
+	ErrCodeNoBootstrapVersion     = 117
+	ErrCodeSourceFetch            = 118
+	ErrCodeSourceBuild            = 119
+	ErrCodeInvalidOutputFormat    = 120
+	ErrCodeAborted                = 121
+	ErrCodeArchivePlatform        = 122
+	ErrCodeConfirmationRequired   = 123
+)
+
+// Kinds of errors, which errors.Is tells apart whatever their message or code, e.g.
+// errors.Is(err, domain.ErrNotFound).
+var (
+	ErrInvalid    = errors.New("invalid input")
+	ErrNotFound   = errors.New("not found")
+	ErrNetwork    = errors.New("network failure")
+	ErrChecksum   = errors.New("verification failure")
+	ErrFilesystem = errors.New("filesystem failure")
+	ErrAborted    = errors.New("aborted")
 )
 
+// unexpectedMessages describe what failed for the codes of NewUnexpectedError.
+var unexpectedMessages = map[int]string{
+	ErrCodeCheckUserHome:               "could not find the home directory",
+	ErrCodeCheckVersion:                "could not check the version in the release index",
+	ErrCodeDownloadRemoveDir:           "could not remove the previous download",
+	ErrCodeDownloadCreateFile:          "could not create the download file",
+	ErrCodeDownloadVersion:             "could not download the archive",
+	ErrCodeChecksumDownload:            "could not get the checksum from the release index",
+	ErrCodeChecksumOpenFile:            "could not open the archive",
+	ErrCodeChecksumCopy:                "could not read the archive",
+	ErrCodeChecksumMismatch:            "the checksum of the archive doesn't match",
+	ErrCodeRemoveVersion:               "could not move the installed version aside",
+	ErrCodeUntarCreateDir:              "could not create the staging directory",
+	ErrCodeUntarExtract:                "could not extract the archive",
+	ErrCodeAddToPathNoShellsFound:      "no shell rc file found",
+	ErrCodeAddToPathStat:               "could not find the shell rc file",
+	ErrCodeAddToPathRead:               "could not read the shell rc file",
+	ErrCodeAddToPathWrite:              "could not write the shell rc file",
+	ErrCodeRemoveCurrentVersion:        "could not unlink the current version",
+	ErrCodeRemoveFromPathNoShellsFound: "no shell rc file found",
+	ErrCodeRemoveFromPathStat:          "could not find the shell rc file",
+	ErrCodeRemoveFromPathRead:          "could not read the shell rc file",
+	ErrCodeRemoveFromPathWrite:         "could not write the shell rc file",
+	ErrCodeSetCurrentVersion:           "could not link the current version",
+	ErrCodeListLocalVersions:           "could not list the installed versions",
+	ErrCodeGetCurrentVersion:           "could not read the current version link",
+	ErrCodeCheckLocalVersion:           "could not check the installed version",
+	ErrCodeResolveWorkingDir:           "could not get the working directory",
+	ErrCodeResolveReadFile:             "could not read the version file",
+	ErrCodeCreateShims:                 "could not create the shims",
+	ErrCodeGetExecutable:               "could not find the govm executable",
+	ErrCodeRunCommand:                  "could not run the command",
+	ErrCodeDownloadCreateDir:           "could not create the cache directory",
+	ErrCodeCacheCreateDir:              "could not create the cache directory",
+	ErrCodeCacheStore:                  "could not store the archive in the cache",
+	ErrCodeCacheList:                   "could not list the cache",
+	ErrCodeCacheRemove:                 "could not remove the cached archives",
+	ErrCodeReadConfig:                  "could not read the config file",
+	ErrCodeWriteConfig:                 "could not write the config file",
+	ErrCodeInstallVersion:              "could not move the new version into place",
+	ErrCodeRollback:                    "could not roll back the changes",
+	ErrCodeSourceCreateDir:             "could not clear the staging directory",
+	ErrCodeListVersions:                "could not get the release index",
+}
+
 type baseError struct {
 	Message string
 	Code    int
+	// kind is the sentinel the error matches with errors.Is, if any.
+	kind error
+	// cause is the error that led to this one, returned by Unwrap.
+	cause error
 }
 
 func (e *baseError) Error() string {
 	return fmt.Sprintf("Error: %s Code: %d", e.Message, e.Code)
 }
 
-func NewUnexpectedError(code int) error {
+func (e *baseError) Unwrap() error {
+	return e.cause
+}
+
+func (e *baseError) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
+// Causes lists the chain of errors that led to err, outermost first, as shown with
+// --verbose.
+func Causes(err error) []string {
+	var causes []string
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause.Error())
+	}
+	return causes
+}
+
+// withReason appends the reason of cause to message, e.g. "could not write the shell
+// rc file: permission denied on /home/user/.zshrc".
+func withReason(message string, cause error) string {
+	if cause == nil {
+		return message
+	}
+
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	var urlErr *url.Error
+	switch {
+	case errors.As(cause, &pathErr):
+		return fmt.Sprintf("%s: %s on %s", message, pathErr.Err, pathErr.Path)
+	case errors.As(cause, &linkErr):
+		return fmt.Sprintf("%s: %s on %s", message, linkErr.Err, linkErr.New)
+	case errors.As(cause, &urlErr):
+		return fmt.Sprintf("%s: %s on %s", message, urlErr.Err, urlErr.URL)
+	default:
+		return fmt.Sprintf("%s: %s", message, cause)
+	}
+}
+
+// NewUnexpectedError is an error of govm or of its environment rather than of the
+// user, caused by cause, which may be nil.
+func NewUnexpectedError(code int, cause error) error {
+	message, ok := unexpectedMessages[code]
+	if !ok {
+		message = errMessageUnexpected
+	}
 	return &baseError{
-		Message: errMessageUnexpected,
+		Message: withReason(message, cause),
 		Code:    code,
+		kind:    unexpectedKind(code),
+		cause:   cause,
+	}
+}
+
+func unexpectedKind(code int) error {
+	switch code {
+	case ErrCodeListVersions, ErrCodeCheckVersion, ErrCodeDownloadVersion, ErrCodeChecksumDownload:
+		return ErrNetwork
+	case ErrCodeChecksumMismatch:
+		return ErrChecksum
+	case ErrCodeRunCommand:
+		return nil
+	default:
+		return ErrFilesystem
 	}
 }
 
 func NewVersionNotAvailableError(version string) error {
 	return &baseError{
 		Message: fmt.Sprintf(errMessageVersionNotAvailable, version),
-		Code:    1,
+		Code:    ErrCodeVersionNotAvailable,
+		kind:    ErrNotFound,
+	}
+}
+
+func NewVersionNotAvailableForError(version string, platform Platform) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageVersionNotAvailableFor, version, platform),
+		Code:    ErrCodeVersionNotAvailableFor,
+		kind:    ErrNotFound,
 	}
 }
 
 func NewNoUpdatesAvailableError(strategy UpdateStrategy, version string) error {
 	return &baseError{
 		Message: fmt.Sprintf(errMessageNoUpdatesAvailable, string(strategy), version),
-		Code:    1,
+		Code:    ErrCodeNoUpdatesAvailable,
+		kind:    ErrNotFound,
 	}
 }
 
 func NewNoGoInstallationsFoundError() error {
 	return &baseError{
 		Message: errMessageNoGoInstallationsFound,
-		Code:    1,
+		Code:    ErrCodeNoGoInstallationsFound,
+		kind:    ErrNotFound,
 	}
 }
 
 func NewInvalidUpdateStrategyError(strategy UpdateStrategy) error {
 	return &baseError{
 		Message: fmt.Sprintf(errMessageInvalidUpdateStrategy, string(strategy)),
-		Code:    1,
+		Code:    ErrCodeInvalidUpdateStrategy,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewVersionNotInstalledError(version string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageVersionNotInstalled, version, version),
+		Code:    ErrCodeVersionNotInstalled,
+		kind:    ErrNotFound,
+	}
+}
+
+func NewNoPinnedVersionFoundError(dir string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageNoPinnedVersionFound, dir),
+		Code:    ErrCodeNoPinnedVersionFound,
+		kind:    ErrNotFound,
+	}
+}
+
+func NewInvalidShellError(shell Shell) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageInvalidShell, string(shell)),
+		Code:    ErrCodeInvalidShell,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewNoActiveVersionError() error {
+	return &baseError{
+		Message: errMessageNoActiveVersion,
+		Code:    ErrCodeNoActiveVersion,
+		kind:    ErrNotFound,
+	}
+}
+
+func NewInvalidCacheKeepError(keep int) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageInvalidCacheKeep, keep),
+		Code:    ErrCodeInvalidCacheKeep,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewNotAvailableOfflineError(resource string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageNotAvailableOffline, resource),
+		Code:    ErrCodeNotAvailableOffline,
+		kind:    ErrNetwork,
+	}
+}
+
+func NewInvalidConfigKeyError(key string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageInvalidConfigKey, key, strings.Join(ConfigKeys(), ", ")),
+		Code:    ErrCodeInvalidConfigKey,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewInvalidConfigValueError(key, value string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageInvalidConfigValue, value, key),
+		Code:    ErrCodeInvalidConfigValue,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewCancelledError() error {
+	return &baseError{
+		Message: errMessageCancelled,
+		Code:    ErrCodeCancelled,
+		kind:    ErrAborted,
+	}
+}
+
+func NewSignatureNotVerifiedError(filename string, cause error) error {
+	return &baseError{
+		Message: withReason(fmt.Sprintf(errMessageSignatureNotVerified, filename), cause),
+		Code:    ErrCodeSignatureNotVerified,
+		kind:    ErrChecksum,
+		cause:   cause,
+	}
+}
+
+func NewArchivePlatformError(archive string, platform Platform, target Platform) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageArchivePlatform, archive, platform, target),
+		Code:    ErrCodeArchivePlatform,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewInvalidArchiveError(archive string, cause error) error {
+	return &baseError{
+		Message: withReason(fmt.Sprintf(errMessageInvalidArchive, archive), cause),
+		Code:    ErrCodeInvalidArchive,
+		kind:    ErrChecksum,
+		cause:   cause,
+	}
+}
+
+func NewUnstableVersionError(version string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageUnstableVersion, version),
+		Code:    ErrCodeUnstableVersion,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewNoBootstrapVersionError() error {
+	return &baseError{
+		Message: errMessageNoBootstrapVersion,
+		Code:    ErrCodeNoBootstrapVersion,
+		kind:    ErrNotFound,
+	}
+}
+
+func NewSourceFetchError(ref, repo string, cause error) error {
+	return &baseError{
+		Message: withReason(fmt.Sprintf(errMessageSourceFetch, ref, repo), cause),
+		Code:    ErrCodeSourceFetch,
+		kind:    ErrNetwork,
+		cause:   cause,
+	}
+}
+
+func NewSourceBuildError(ref string, cause error) error {
+	return &baseError{
+		Message: withReason(fmt.Sprintf(errMessageSourceBuild, ref), cause),
+		Code:    ErrCodeSourceBuild,
+		cause:   cause,
+	}
+}
+
+func NewInvalidOutputFormatError(format string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageInvalidOutputFormat, format, strings.Join(OutputFormats(), ", ")),
+		Code:    ErrCodeInvalidOutputFormat,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewConfirmationRequiredError(command string) error {
+	return &baseError{
+		Message: fmt.Sprintf(errMessageConfirmationRequired, command),
+		Code:    ErrCodeConfirmationRequired,
+		kind:    ErrInvalid,
+	}
+}
+
+func NewAbortedError() error {
+	return &baseError{
+		Message: errMessageAborted,
+		Code:    ErrCodeAborted,
+		kind:    ErrAborted,
 	}
 }
@@ -1,7 +1,12 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,17 +14,129 @@ import (
 
 func TestNewUnexpectedError(t *testing.T) {
 	// Arrange
-	code := ErrCodeListVersions
+	cause := &fs.PathError{Op: "open", Path: "/home/user/.zshrc", Err: fs.ErrPermission}
 
 	// Act
-	err := NewUnexpectedError(code)
+	err := NewUnexpectedError(ErrCodeAddToPathWrite, cause)
 
 	// Assert
 	baseErr, ok := err.(*baseError)
 	assert.True(t, ok)
-	assert.Equal(t, errMessageUnexpected, baseErr.Message)
-	assert.Equal(t, code, baseErr.Code)
-	assert.Equal(t, "Error: an unexpected error occurred, please verify govm.log for more information Code: 1", err.Error())
+	assert.Equal(t, "could not write the shell rc file: permission denied on /home/user/.zshrc", baseErr.Message)
+	assert.Equal(t, ErrCodeAddToPathWrite, baseErr.Code)
+	assert.Equal(t, "Error: could not write the shell rc file: permission denied on /home/user/.zshrc Code: 16", err.Error())
+	assert.ErrorIs(t, err, ErrFilesystem)
+	assert.ErrorIs(t, err, fs.ErrPermission)
+	assert.Same(t, cause, errors.Unwrap(err))
+}
+
+func TestNewUnexpectedErrorWithoutCause(t *testing.T) {
+	// Act
+	err := NewUnexpectedError(ErrCodeAddToPathNoShellsFound, nil)
+
+	// Assert
+	assert.Equal(t, "Error: no shell rc file found Code: 13", err.Error())
+	assert.Nil(t, errors.Unwrap(err))
+}
+
+func TestNewUnexpectedErrorUnknownCode(t *testing.T) {
+	// Act
+	err := NewUnexpectedError(99, errors.New("boom"))
+
+	// Assert
+	assert.Equal(t, "Error: an unexpected error occurred, please verify govm.log for more information: boom Code: 99", err.Error())
+	assert.ErrorIs(t, err, ErrFilesystem)
+}
+
+func TestNewUnexpectedErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int
+		expected error
+	}{
+		{name: "ListVersions", code: ErrCodeListVersions, expected: ErrNetwork},
+		{name: "DownloadVersion", code: ErrCodeDownloadVersion, expected: ErrNetwork},
+		{name: "ChecksumMismatch", code: ErrCodeChecksumMismatch, expected: ErrChecksum},
+		{name: "SetCurrentVersion", code: ErrCodeSetCurrentVersion, expected: ErrFilesystem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := NewUnexpectedError(tt.code, errors.New("error"))
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestNewUnexpectedErrorRunCommand(t *testing.T) {
+	// Act
+	err := NewUnexpectedError(ErrCodeRunCommand, errors.New("error"))
+
+	// Assert
+	for _, kind := range []error{ErrInvalid, ErrNotFound, ErrNetwork, ErrChecksum, ErrFilesystem, ErrAborted} {
+		assert.NotErrorIs(t, err, kind)
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("installing: %w", NewUnexpectedError(ErrCodeListVersions, errors.New("error")))
+
+	// Act
+	var baseErr *baseError
+	ok := errors.As(err, &baseErr)
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, ErrCodeListVersions, baseErr.Code)
+	assert.ErrorIs(t, err, ErrNetwork)
+}
+
+func TestCauses(t *testing.T) {
+	// Arrange
+	cause := fmt.Errorf("dial tcp: %w", errors.New("connection refused"))
+
+	// Act
+	causes := Causes(NewUnexpectedError(ErrCodeListVersions, cause))
+
+	// Assert
+	assert.Equal(t, []string{"dial tcp: connection refused", "connection refused"}, causes)
+}
+
+func TestCausesWithoutCause(t *testing.T) {
+	// Act
+	causes := Causes(NewVersionNotAvailableError("go1.22.3"))
+
+	// Assert
+	assert.Empty(t, causes)
+}
+
+func TestWithReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		cause    error
+		expected string
+	}{
+		{name: "Nil", cause: nil, expected: "failed"},
+		{name: "PathError", cause: &fs.PathError{Op: "open", Path: "/tmp/go.tar.gz", Err: fs.ErrNotExist}, expected: "failed: file does not exist on /tmp/go.tar.gz"},
+		{name: "LinkError", cause: &os.LinkError{Op: "symlink", Old: "/tmp/a", New: "/tmp/b", Err: fs.ErrExist}, expected: "failed: file already exists on /tmp/b"},
+		{name: "URLError", cause: &url.Error{Op: "Get", URL: "https://go.dev/dl/", Err: errors.New("connection refused")}, expected: "failed: connection refused on https://go.dev/dl/"},
+		{name: "Wrapped", cause: fmt.Errorf("reading: %w", &fs.PathError{Op: "read", Path: "/tmp/VERSION", Err: fs.ErrClosed}), expected: "failed: file already closed on /tmp/VERSION"},
+		{name: "Other", cause: errors.New("boom"), expected: "failed: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			message := withReason("failed", tt.cause)
+
+			// Assert
+			assert.Equal(t, tt.expected, message)
+		})
+	}
 }
 
 func TestNewVersionNotAvailableError(t *testing.T) {
@@ -33,8 +150,8 @@ func TestNewVersionNotAvailableError(t *testing.T) {
 	baseErr, ok := err.(*baseError)
 	assert.True(t, ok)
 	assert.Equal(t, fmt.Sprintf(errMessageVersionNotAvailable, version), baseErr.Message)
-	assert.Equal(t, 1, baseErr.Code)
-	assert.Equal(t, "Error: go version \"1.16.0\" is not available Code: 1", err.Error())
+	assert.Equal(t, ErrCodeVersionNotAvailable, baseErr.Code)
+	assert.Equal(t, "Error: go version \"1.16.0\" is not available Code: 100", err.Error())
 }
 
 func TestNewNoUpdatesAvailableError(t *testing.T) {
@@ -49,8 +166,8 @@ func TestNewNoUpdatesAvailableError(t *testing.T) {
 	baseErr, ok := err.(*baseError)
 	assert.True(t, ok)
 	assert.Equal(t, fmt.Sprintf(errMessageNoUpdatesAvailable, string(strategy), version), baseErr.Message)
-	assert.Equal(t, 1, baseErr.Code)
-	assert.Equal(t, "Error: no patch updates available for version \"1.16.0\" Code: 1", err.Error())
+	assert.Equal(t, ErrCodeNoUpdatesAvailable, baseErr.Code)
+	assert.Equal(t, "Error: no patch updates available for version \"1.16.0\" Code: 102", err.Error())
 }
 
 func TestNewNoGoInstallationsFoundError(t *testing.T) {
@@ -61,8 +178,8 @@ func TestNewNoGoInstallationsFoundError(t *testing.T) {
 	baseErr, ok := err.(*baseError)
 	assert.True(t, ok)
 	assert.Equal(t, errMessageNoGoInstallationsFound, baseErr.Message)
-	assert.Equal(t, 1, baseErr.Code)
-	assert.Equal(t, "Error: no go installations found Code: 1", err.Error())
+	assert.Equal(t, ErrCodeNoGoInstallationsFound, baseErr.Code)
+	assert.Equal(t, "Error: no go installations found Code: 103", err.Error())
 }
 
 func TestNewInvalidUpdateStrategyError(t *testing.T) {
@@ -76,6 +193,265 @@ func TestNewInvalidUpdateStrategyError(t *testing.T) {
 	baseErr, ok := err.(*baseError)
 	assert.True(t, ok)
 	assert.Equal(t, fmt.Sprintf(errMessageInvalidUpdateStrategy, string(strategy)), baseErr.Message)
-	assert.Equal(t, 1, baseErr.Code)
-	assert.Equal(t, "Error: \"major\" is not a valid update strategy Code: 1", err.Error())
+	assert.Equal(t, ErrCodeInvalidUpdateStrategy, baseErr.Code)
+	assert.Equal(t, "Error: \"major\" is not a valid update strategy Code: 104", err.Error())
+}
+
+func TestNewVersionNotInstalledError(t *testing.T) {
+	// Arrange
+	version := "go1.22.3"
+
+	// Act
+	err := NewVersionNotInstalledError(version)
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageVersionNotInstalled, version, version), baseErr.Message)
+	assert.Equal(t, ErrCodeVersionNotInstalled, baseErr.Code)
+	assert.Equal(t, "Error: go version \"go1.22.3\" is not installed, run \"govm install go1.22.3\" first Code: 105", err.Error())
+}
+
+func TestNewNoPinnedVersionFoundError(t *testing.T) {
+	// Arrange
+	dir := "/home/user/project"
+
+	// Act
+	err := NewNoPinnedVersionFoundError(dir)
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageNoPinnedVersionFound, dir), baseErr.Message)
+	assert.Equal(t, ErrCodeNoPinnedVersionFound, baseErr.Code)
+	assert.Equal(t, "Error: no go version specified and no .go-version or go.mod found in \"/home/user/project\" or its parents Code: 106", err.Error())
+}
+
+func TestNewInvalidShellError(t *testing.T) {
+	// Arrange
+	shell := Shell("csh")
+
+	// Act
+	err := NewInvalidShellError(shell)
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageInvalidShell, string(shell)), baseErr.Message)
+	assert.Equal(t, ErrCodeInvalidShell, baseErr.Code)
+	assert.Equal(t, "Error: \"csh\" is not a supported shell, use one of: bash, zsh, fish Code: 107", err.Error())
+}
+
+func TestNewNoActiveVersionError(t *testing.T) {
+	// Act
+	err := NewNoActiveVersionError()
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, errMessageNoActiveVersion, baseErr.Message)
+	assert.Equal(t, ErrCodeNoActiveVersion, baseErr.Code)
+	assert.Equal(t, "Error: no go version selected, run \"govm use [version]\" first Code: 108", err.Error())
+}
+
+func TestNewInvalidCacheKeepError(t *testing.T) {
+	// Act
+	err := NewInvalidCacheKeepError(-1)
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageInvalidCacheKeep, -1), baseErr.Message)
+	assert.Equal(t, ErrCodeInvalidCacheKeep, baseErr.Code)
+	assert.Equal(t, "Error: -1 is not a valid number of archives to keep Code: 109", err.Error())
+}
+
+func TestNewNotAvailableOfflineError(t *testing.T) {
+	// Act
+	err := NewNotAvailableOfflineError("the Go release index")
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageNotAvailableOffline, "the Go release index"), baseErr.Message)
+	assert.Equal(t, ErrCodeNotAvailableOffline, baseErr.Code)
+	assert.Equal(t, "Error: the Go release index is not available offline, run the command again without --offline Code: 110", err.Error())
+}
+
+func TestNewInvalidConfigKeyError(t *testing.T) {
+	// Act
+	err := NewInvalidConfigKeyError("proxy")
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageInvalidConfigKey, "proxy", strings.Join(ConfigKeys(), ", ")), baseErr.Message)
+	assert.Equal(t, ErrCodeInvalidConfigKey, baseErr.Code)
+	assert.Equal(t, "Error: \"proxy\" is not a valid config key, use one of: mirror, index_url, root, update_strategy, shell_integration, cache, index_ttl, color, signature Code: 111", err.Error())
+}
+
+func TestNewInvalidConfigValueError(t *testing.T) {
+	// Act
+	err := NewInvalidConfigValueError("color", "rainbow")
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageInvalidConfigValue, "rainbow", "color"), baseErr.Message)
+	assert.Equal(t, ErrCodeInvalidConfigValue, baseErr.Code)
+	assert.Equal(t, "Error: \"rainbow\" is not a valid value for color Code: 112", err.Error())
+}
+
+func TestNewCancelledError(t *testing.T) {
+	// Act
+	err := NewCancelledError()
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, errMessageCancelled, baseErr.Message)
+	assert.Equal(t, ErrCodeCancelled, baseErr.Code)
+	assert.Equal(t, "Error: operation cancelled, changes made so far were rolled back Code: 113", err.Error())
+}
+
+func TestNewSignatureNotVerifiedError(t *testing.T) {
+	// Arrange
+	cause := errors.New("openpgp: invalid signature")
+
+	// Act
+	err := NewSignatureNotVerifiedError("go1.22.3.linux-amd64.tar.gz", cause)
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageSignatureNotVerified, "go1.22.3.linux-amd64.tar.gz")+": openpgp: invalid signature", baseErr.Message)
+	assert.Equal(t, ErrCodeSignatureNotVerified, baseErr.Code)
+	assert.Equal(t, "Error: the signature of \"go1.22.3.linux-amd64.tar.gz\" could not be verified: openpgp: invalid signature Code: 114", err.Error())
+	assert.ErrorIs(t, err, ErrChecksum)
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestNewArchivePlatformError(t *testing.T) {
+	// Act
+	err := NewArchivePlatformError("go.tar.gz", Platform{OS: "darwin", Arch: "arm64"}, Platform{OS: "linux", Arch: "amd64"})
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageArchivePlatform, "go.tar.gz", "darwin/arm64", "linux/amd64"), baseErr.Message)
+	assert.Equal(t, ErrCodeArchivePlatform, baseErr.Code)
+	assert.Equal(t, "Error: \"go.tar.gz\" is a go archive for darwin/arm64, not for linux/amd64 Code: 122", err.Error())
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestNewInvalidArchiveError(t *testing.T) {
+	// Act
+	err := NewInvalidArchiveError("go.tar.gz", fs.ErrNotExist)
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageInvalidArchive, "go.tar.gz")+": file does not exist", baseErr.Message)
+	assert.Equal(t, ErrCodeInvalidArchive, baseErr.Code)
+	assert.Equal(t, "Error: \"go.tar.gz\" is not a valid go archive: file does not exist Code: 115", err.Error())
+	assert.ErrorIs(t, err, ErrChecksum)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestNewUnstableVersionError(t *testing.T) {
+	// Act
+	err := NewUnstableVersionError("go1.23rc1")
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageUnstableVersion, "go1.23rc1"), baseErr.Message)
+	assert.Equal(t, ErrCodeUnstableVersion, baseErr.Code)
+	assert.Equal(t, "Error: go version \"go1.23rc1\" is not a stable release, use --unstable to install it Code: 116", err.Error())
+}
+
+func TestNewNoBootstrapVersionError(t *testing.T) {
+	// Act
+	err := NewNoBootstrapVersionError()
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, errMessageNoBootstrapVersion, baseErr.Message)
+	assert.Equal(t, ErrCodeNoBootstrapVersion, baseErr.Code)
+	assert.Equal(t, "Error: building go from source needs an installed go version to bootstrap from, run \"govm install [version]\" first Code: 117", err.Error())
+}
+
+func TestNewSourceFetchError(t *testing.T) {
+	// Act
+	err := NewSourceFetchError("master", "https://go.googlesource.com/go", errors.New("exit status 128"))
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageSourceFetch, "master", "https://go.googlesource.com/go")+": exit status 128", baseErr.Message)
+	assert.Equal(t, ErrCodeSourceFetch, baseErr.Code)
+	assert.Equal(t, "Error: \"master\" could not be fetched from \"https://go.googlesource.com/go\": exit status 128 Code: 118", err.Error())
+	assert.ErrorIs(t, err, ErrNetwork)
+}
+
+func TestNewSourceBuildError(t *testing.T) {
+	// Act
+	err := NewSourceBuildError("master", errors.New("exit status 2"))
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageSourceBuild, "master")+": exit status 2", baseErr.Message)
+	assert.Equal(t, ErrCodeSourceBuild, baseErr.Code)
+	assert.Equal(t, "Error: building \"master\" failed, its output is in govm.log: exit status 2 Code: 119", err.Error())
+}
+
+func TestNewVersionNotAvailableForError(t *testing.T) {
+	// Act
+	err := NewVersionNotAvailableForError("go1.22.3", Platform{OS: "plan9", Arch: "arm"})
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageVersionNotAvailableFor, "go1.22.3", "plan9/arm"), baseErr.Message)
+	assert.Equal(t, ErrCodeVersionNotAvailableFor, baseErr.Code)
+	assert.Equal(t, "Error: go version \"go1.22.3\" is not available for plan9/arm Code: 101", err.Error())
+}
+
+func TestNewInvalidOutputFormatError(t *testing.T) {
+	// Act
+	err := NewInvalidOutputFormatError("xml")
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageInvalidOutputFormat, "xml", "text, json, yaml"), baseErr.Message)
+	assert.Equal(t, ErrCodeInvalidOutputFormat, baseErr.Code)
+	assert.Equal(t, "Error: \"xml\" is not a valid output format, use one of: text, json, yaml Code: 120", err.Error())
+}
+
+func TestNewConfirmationRequiredError(t *testing.T) {
+	// Act
+	err := NewConfirmationRequiredError("uninstall")
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, fmt.Sprintf(errMessageConfirmationRequired, "uninstall"), baseErr.Message)
+	assert.Equal(t, ErrCodeConfirmationRequired, baseErr.Code)
+	assert.Equal(t, "Error: stdin is not a terminal, pass --yes to uninstall without confirmation Code: 123", err.Error())
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestNewAbortedError(t *testing.T) {
+	// Act
+	err := NewAbortedError()
+
+	// Assert
+	baseErr, ok := err.(*baseError)
+	assert.True(t, ok)
+	assert.Equal(t, errMessageAborted, baseErr.Message)
+	assert.Equal(t, ErrCodeAborted, baseErr.Code)
+	assert.Equal(t, "Error: aborted by user Code: 121", err.Error())
 }
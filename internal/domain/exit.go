@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of govm, so scripts can tell why a command failed. exec and the shims
+// exit with the code of the command they run instead, once it's started.
+const (
+	ExitSuccess = 0
+	// ExitFailure is any other error, e.g. invalid arguments.
+	ExitFailure = 1
+	// ExitNotFound is a version that isn't available or installed, or no version
+	// pinned or in use.
+	ExitNotFound = 2
+	// ExitNetwork is a failure to reach the release index, the mirror or a repository.
+	ExitNetwork = 3
+	// ExitChecksum is an archive whose checksum or signature doesn't match.
+	ExitChecksum = 4
+	// ExitFilesystem is a failure to read or write the files of govm or the shell rc
+	// files.
+	ExitFilesystem = 5
+	// ExitAborted is an action aborted by the user or interrupted.
+	ExitAborted = 6
+)
+
+// CommandExitError is a command run by exec or a shim that exited with a non-zero
+// code, which govm exits with too.
+type CommandExitError struct {
+	Code int
+}
+
+func NewCommandExitError(code int) error {
+	return &CommandExitError{Code: code}
+}
+
+func (e *CommandExitError) Error() string {
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
+// ExitCode is the exit code of govm for err, from the kind of the error.
+func ExitCode(err error) int {
+	var exitErr *CommandExitError
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	case errors.Is(err, ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, ErrNetwork):
+		return ExitNetwork
+	case errors.Is(err, ErrChecksum):
+		return ExitChecksum
+	case errors.Is(err, ErrFilesystem):
+		return ExitFilesystem
+	case errors.Is(err, ErrAborted):
+		return ExitAborted
+	default:
+		return ExitFailure
+	}
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "No Error", err: nil, expected: domain.ExitSuccess},
+		{name: "Other Error", err: errors.New("accepts at most 1 arg(s), received 2"), expected: domain.ExitFailure},
+		{name: "Invalid Input", err: domain.NewInvalidUpdateStrategyError("latest"), expected: domain.ExitFailure},
+		{name: "Version Not Available", err: domain.NewVersionNotAvailableError("go1.99.0"), expected: domain.ExitNotFound},
+		{name: "Version Not Installed", err: domain.NewVersionNotInstalledError("go1.22.3"), expected: domain.ExitNotFound},
+		{name: "No Go Installations", err: domain.NewNoGoInstallationsFoundError(), expected: domain.ExitNotFound},
+		{name: "Release Index", err: domain.NewUnexpectedError(domain.ErrCodeListVersions, errors.New("error")), expected: domain.ExitNetwork},
+		{name: "Download", err: domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, errors.New("error")), expected: domain.ExitNetwork},
+		{name: "Offline", err: domain.NewNotAvailableOfflineError("release index"), expected: domain.ExitNetwork},
+		{name: "Checksum Mismatch", err: domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, errors.New("error")), expected: domain.ExitChecksum},
+		{name: "Signature", err: domain.NewSignatureNotVerifiedError("go1.22.3.linux-amd64.tar.gz", errors.New("error")), expected: domain.ExitChecksum},
+		{name: "Shell Rc File", err: domain.NewUnexpectedError(domain.ErrCodeAddToPathWrite, errors.New("error")), expected: domain.ExitFilesystem},
+		{name: "Home", err: domain.NewUnexpectedError(domain.ErrCodeCheckUserHome, errors.New("error")), expected: domain.ExitFilesystem},
+		{name: "Run Command", err: domain.NewUnexpectedError(domain.ErrCodeRunCommand, errors.New("error")), expected: domain.ExitFailure},
+		{name: "Archive Platform", err: domain.NewArchivePlatformError("go.tar.gz", domain.Platform{OS: "darwin", Arch: "arm64"}, domain.HostPlatform()), expected: domain.ExitFailure},
+		{name: "Confirmation Required", err: domain.NewConfirmationRequiredError("uninstall"), expected: domain.ExitFailure},
+		{name: "Aborted", err: domain.NewAbortedError(), expected: domain.ExitAborted},
+		{name: "Cancelled", err: domain.NewCancelledError(), expected: domain.ExitAborted},
+		{name: "Command Exit", err: domain.NewCommandExitError(42), expected: 42},
+		{name: "Wrapped", err: fmt.Errorf("uninstall: %w", domain.NewNoActiveVersionError()), expected: domain.ExitNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.ExitCode(tt.err))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OutputFormat is how the results of the commands are printed, as given with
+// --output. It's a flag value, so an invalid format is rejected when parsing.
+type OutputFormat string
+
+const (
+	TextOutput OutputFormat = "text"
+	JSONOutput OutputFormat = "json"
+	YAMLOutput OutputFormat = "yaml"
+)
+
+// OutputFormats lists the formats accepted by --output.
+func OutputFormats() []string {
+	return []string{string(TextOutput), string(JSONOutput), string(YAMLOutput)}
+}
+
+func (r *OutputFormat) String() string {
+	return string(*r)
+}
+
+func (r *OutputFormat) Set(value string) error {
+	switch format := OutputFormat(value); format {
+	case TextOutput, JSONOutput, YAMLOutput:
+		*r = format
+		return nil
+	default:
+		return NewInvalidOutputFormatError(value)
+	}
+}
+
+func (r *OutputFormat) Type() string {
+	return "format"
+}
+
+// VersionResult is a version of the release index as listed by "govm list".
+type VersionResult struct {
+	Version   string `json:"version" yaml:"version"`
+	Stable    bool   `json:"stable" yaml:"stable"`
+	Installed bool   `json:"installed" yaml:"installed"`
+	Active    bool   `json:"active" yaml:"active"`
+}
+
+// String marks the version in use with "*" and the other installed ones with "+".
+func (r VersionResult) String() string {
+	if r.Active {
+		return fmt.Sprintf("* %s", r.Version)
+	}
+
+	if r.Installed {
+		return fmt.Sprintf("+ %s", r.Version)
+	}
+
+	return r.Version
+}
+
+type VersionListResult struct {
+	Platform string          `json:"platform" yaml:"platform"`
+	Versions []VersionResult `json:"versions" yaml:"versions"`
+}
+
+// InstallResult is the version put in place by "govm install" or "govm update".
+type InstallResult struct {
+	Version  string `json:"version" yaml:"version"`
+	Platform string `json:"platform" yaml:"platform"`
+	Path     string `json:"path" yaml:"path"`
+	// Checksum is empty for a build from source, or an archive given without --sha256.
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	// PreviousVersion is the version replaced by an update.
+	PreviousVersion     string `json:"previous_version,omitempty" yaml:"previous_version,omitempty"`
+	SignatureUnverified bool   `json:"signature_unverified" yaml:"signature_unverified"`
+	PathUpdated         bool   `json:"path_updated" yaml:"path_updated"`
+}
+
+func NewInstallResult(action Action) InstallResult {
+	return InstallResult{
+		Version:             action.Version,
+		Platform:            action.Target().String(),
+		Path:                action.HomeVersionDir(),
+		Checksum:            action.Checksum,
+		PreviousVersion:     action.InstalledVersion,
+		SignatureUnverified: action.SignatureUnverified,
+		PathUpdated:         action.PathUpdated,
+	}
+}
+
+// UninstallResult lists the versions removed by "govm uninstall".
+type UninstallResult struct {
+	Platform string   `json:"platform" yaml:"platform"`
+	Versions []string `json:"versions" yaml:"versions"`
+}
+
+// ErrorResult is an error as rendered with --output. Code is the code of the govm
+// error, or zero for any other error.
+type ErrorResult struct {
+	Error string `json:"error" yaml:"error"`
+	Code  int    `json:"code" yaml:"code"`
+	// Causes is the chain of errors that led to Error, only set with --verbose.
+	Causes []string `json:"causes,omitempty" yaml:"causes,omitempty"`
+}
+
+func NewErrorResult(err error) ErrorResult {
+	var baseErr *baseError
+	if errors.As(err, &baseErr) {
+		return ErrorResult{Error: baseErr.Message, Code: baseErr.Code}
+	}
+	return ErrorResult{Error: err.Error()}
+}
@@ -0,0 +1,53 @@
+package domain_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputFormat(t *testing.T) {
+	format := domain.TextOutput
+
+	assert.NoError(t, format.Set("json"))
+	assert.Equal(t, domain.JSONOutput, format)
+	assert.Equal(t, "json", format.String())
+	assert.EqualError(t, format.Set("xml"), domain.NewInvalidOutputFormatError("xml").Error())
+	assert.Equal(t, domain.JSONOutput, format)
+}
+
+func TestVersionResult(t *testing.T) {
+	assert.Equal(t, "* 1.20.5", domain.VersionResult{Version: "1.20.5", Installed: true, Active: true}.String())
+	assert.Equal(t, "+ 1.20.6", domain.VersionResult{Version: "1.20.6", Installed: true}.String())
+	assert.Equal(t, "1.20.7", domain.VersionResult{Version: "1.20.7"}.String())
+}
+
+func TestNewInstallResult(t *testing.T) {
+	action := domain.Action{
+		Version:          "go1.22.4",
+		HomeDir:          "/home/user",
+		OS:               "plan9",
+		Arch:             "arm",
+		Checksum:         "abc123",
+		InstalledVersion: "go1.22.3",
+		PathUpdated:      true,
+	}
+
+	assert.Equal(t, domain.InstallResult{
+		Version:         "go1.22.4",
+		Platform:        "plan9/arm",
+		Path:            filepath.Join("/home/user", ".govm", "targets", "plan9-arm", "go1.22.4"),
+		Checksum:        "abc123",
+		PreviousVersion: "go1.22.3",
+		PathUpdated:     true,
+	}, domain.NewInstallResult(action))
+}
+
+func TestNewErrorResult(t *testing.T) {
+	assert.Equal(t, domain.ErrorResult{Error: "no go installations found", Code: domain.ErrCodeNoGoInstallationsFound}, domain.NewErrorResult(domain.NewNoGoInstallationsFoundError()))
+	assert.Equal(t, domain.ErrorResult{Error: "the checksum of the archive doesn't match", Code: domain.ErrCodeChecksumMismatch}, domain.NewErrorResult(domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, nil)))
+	assert.Equal(t, domain.ErrorResult{Error: "boom"}, domain.NewErrorResult(errors.New("boom")))
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "runtime"
+
+// Platform is the operating system and architecture a toolchain is built for, in
+// GOOS and GOARCH terms.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// HostPlatform is the platform govm runs on.
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// IsHost reports whether toolchains for the platform run on this host.
+func (p Platform) IsHost() bool {
+	return p == HostPlatform()
+}
+
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
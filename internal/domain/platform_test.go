@@ -0,0 +1,18 @@
+package domain_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatform(t *testing.T) {
+	host := domain.HostPlatform()
+
+	assert.Equal(t, domain.Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}, host)
+	assert.True(t, host.IsHost())
+	assert.False(t, domain.Platform{OS: "plan9", Arch: "arm"}.IsHost())
+	assert.Equal(t, "plan9/arm", domain.Platform{OS: "plan9", Arch: "arm"}.String())
+}
@@ -0,0 +1,99 @@
+package domain
+
+type Shell string
+
+const (
+	BashShell Shell = "bash"
+	ZshShell  Shell = "zsh"
+	FishShell Shell = "fish"
+
+	// posixHook keeps the bin directory of the version pinned for the current directory
+	// at the front of PATH, restoring the previous PATH and GOROOT when leaving it.
+	posixHook = `_govm_hook() {
+  local bin_dir
+  bin_dir="$(command govm resolve --bin 2>/dev/null)"
+  [ -d "$bin_dir" ] || bin_dir=""
+  [ "$bin_dir" = "$_GOVM_BIN_DIR" ] && return
+  if [ -n "$_GOVM_BIN_DIR" ]; then
+    PATH=":${PATH}:"
+    PATH="${PATH//:"${_GOVM_BIN_DIR}":/:}"
+    PATH="${PATH#:}"
+    PATH="${PATH%:}"
+    if [ -n "$_GOVM_GOROOT" ]; then export GOROOT="$_GOVM_GOROOT"; else unset GOROOT; fi
+    unset _GOVM_BIN_DIR _GOVM_GOROOT
+  fi
+  if [ -n "$bin_dir" ]; then
+    _GOVM_GOROOT="$GOROOT"
+    _GOVM_BIN_DIR="$bin_dir"
+    export GOROOT="${bin_dir%/bin}"
+    export PATH="${bin_dir}:${PATH}"
+  fi
+}`
+
+	bashHook = posixHook + `
+_govm_prompt_hook() {
+  [ "$PWD" = "$_GOVM_PWD" ] && return
+  _GOVM_PWD="$PWD"
+  _govm_hook
+}
+if [[ ";${PROMPT_COMMAND:-};" != *";_govm_prompt_hook;"* ]]; then
+  PROMPT_COMMAND="_govm_prompt_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+_govm_prompt_hook`
+
+	zshHook = posixHook + `
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _govm_hook
+_govm_hook`
+
+	fishHook = `function _govm_hook --on-variable PWD
+    set -l bin_dir (command govm resolve --bin 2>/dev/null)
+    test -d "$bin_dir"; or set bin_dir ""
+    test "$bin_dir" = "$_GOVM_BIN_DIR"; and return
+    if test -n "$_GOVM_BIN_DIR"
+        if set -l index (contains -i -- $_GOVM_BIN_DIR $PATH)
+            set -e PATH[$index]
+        end
+        if set -q _GOVM_GOROOT
+            set -gx GOROOT $_GOVM_GOROOT
+        else
+            set -e GOROOT
+        end
+        set -e _GOVM_BIN_DIR
+        set -e _GOVM_GOROOT
+    end
+    if test -n "$bin_dir"
+        set -q GOROOT; and set -g _GOVM_GOROOT $GOROOT
+        set -g _GOVM_BIN_DIR $bin_dir
+        set -gx GOROOT (dirname $bin_dir)
+        set -gx PATH $bin_dir $PATH
+    end
+end
+_govm_hook`
+)
+
+func (s Shell) CheckShell() error {
+	switch s {
+	case BashShell, ZshShell, FishShell:
+		return nil
+	default:
+		return NewInvalidShellError(s)
+	}
+}
+
+func (s Shell) Hook() string {
+	switch s {
+	case BashShell:
+		return bashHook
+	case ZshShell:
+		return zshHook
+	case FishShell:
+		return fishHook
+	default:
+		return ""
+	}
+}
+
+func Shells() []string {
+	return []string{string(BashShell), string(ZshShell), string(FishShell)}
+}
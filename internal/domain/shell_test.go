@@ -0,0 +1,28 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShell(t *testing.T) {
+	for _, s := range domain.Shells() {
+		shell := domain.Shell(s)
+
+		assert.NoError(t, shell.CheckShell())
+		assert.Contains(t, shell.Hook(), "govm resolve --bin")
+	}
+
+	assert.Contains(t, domain.BashShell.Hook(), "PROMPT_COMMAND")
+	assert.Contains(t, domain.ZshShell.Hook(), "add-zsh-hook chpwd _govm_hook")
+	assert.Contains(t, domain.FishShell.Hook(), "--on-variable PWD")
+}
+
+func TestShell_InvalidShellError(t *testing.T) {
+	shell := domain.Shell("csh")
+
+	assert.Error(t, shell.CheckShell())
+	assert.Empty(t, shell.Hook())
+}
@@ -1,10 +1,5 @@
 package domain
 
-import (
-	"fmt"
-	"runtime"
-)
-
 type FileResponse struct {
 	Filename string `json:"filename"`
 	OS       string `json:"os"`
@@ -19,28 +14,32 @@ type VersionResponse struct {
 	Files   []FileResponse
 }
 
-func (v VersionResponse) IsCompatible() bool {
+// IsCompatible reports whether the version has an archive for platform.
+func (v VersionResponse) IsCompatible(platform Platform) bool {
 	for _, f := range v.Files {
-		if f.Kind == "archive" && f.OS == runtime.GOOS && f.Arch == runtime.GOARCH {
+		if f.Kind == "archive" && f.OS == platform.OS && f.Arch == platform.Arch {
 			return true
 		}
 	}
 	return false
 }
 
-func (v VersionResponse) String(currentVersion string) string {
-
-	if v.Version == currentVersion {
-		return fmt.Sprintf("* %s", v.Version)
-	}
-
-	return v.Version
-}
-
 type VersionsResponse struct {
 	Versions []VersionResponse
 }
 
+// Stable returns the versions that are stable releases, leaving out release
+// candidates and betas.
+func (v VersionsResponse) Stable() VersionsResponse {
+	stable := make([]VersionResponse, 0, len(v.Versions))
+	for _, version := range v.Versions {
+		if version.Stable {
+			stable = append(stable, version)
+		}
+	}
+	return VersionsResponse{Versions: stable}
+}
+
 func (v VersionsResponse) StringSlice() []string {
 	versions := make([]string, len(v.Versions))
 	for i, version := range v.Versions {
@@ -39,10 +39,24 @@ func TestVersionsResponse(t *testing.T) {
 		},
 	}
 
-	assert.True(t, versions.Versions[0].IsCompatible())
-	assert.Equal(t, "* 1.20.5", versions.Versions[0].String("1.20.5"))
-	assert.False(t, versions.Versions[1].IsCompatible())
-	assert.Equal(t, "1.20.6", versions.Versions[1].String("1.20.5"))
+	assert.True(t, versions.Versions[0].IsCompatible(domain.HostPlatform()))
+	assert.False(t, versions.Versions[1].IsCompatible(domain.HostPlatform()))
+	assert.True(t, versions.Versions[1].IsCompatible(domain.Platform{OS: "solaris", Arch: runtime.GOARCH}))
 	assert.Contains(t, versions.StringSlice(), "1.20.5")
 	assert.Contains(t, versions.StringSlice(), "1.20.6")
 }
+
+func TestVersionsResponseStable(t *testing.T) {
+
+	versions := domain.VersionsResponse{
+		Versions: []domain.VersionResponse{
+			{Version: "go1.23rc1"},
+			{Version: "go1.22.5", Stable: true},
+			{Version: "go1.22rc2"},
+			{Version: "go1.21.12", Stable: true},
+		},
+	}
+
+	assert.Equal(t, []string{"go1.22.5", "go1.21.12"}, versions.Stable().StringSlice())
+	assert.Len(t, versions.Versions, 4)
+}
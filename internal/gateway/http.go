@@ -3,80 +3,257 @@ package gateway
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"os"
-	"runtime"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sbonaiva/govm/internal/domain"
 )
 
 type HttpGateway interface {
-	GetVersions(ctx context.Context) (domain.VersionsResponse, error)
-	GetChecksum(ctx context.Context, version string) (string, error)
-	VersionExists(ctx context.Context, version string) (bool, error)
-	DownloadVersion(ctx context.Context, action *domain.Action, file *os.File) error
+	// GetVersions returns the versions with an archive for platform, release
+	// candidates and betas included. Use VersionsResponse.Stable to leave them out.
+	GetVersions(ctx context.Context, platform domain.Platform) (domain.VersionsResponse, error)
+	GetChecksum(ctx context.Context, version string, platform domain.Platform) (string, error)
+	VersionExists(ctx context.Context, version string, platform domain.Platform) (bool, error)
+	DownloadVersion(ctx context.Context, action *domain.Action, file *os.File, progress func(written int64, total int64)) error
+	StreamVersion(ctx context.Context, action *domain.Action, writer io.Writer, progress func(written int64, total int64)) error
+	DownloadURL(ctx context.Context, url string, file *os.File, progress func(written int64, total int64)) error
+	GetSignature(ctx context.Context, action *domain.Action) ([]byte, error)
 }
 
 type HttpConfig struct {
 	GoVersionURL  string
 	GoDownloadURL string
+	// IndexCacheFile is where the release index is kept between runs. Caching
+	// is disabled when empty.
+	IndexCacheFile string
+	// IndexTTL is how long a cached index is used before revalidating it.
+	IndexTTL time.Duration
+	// Offline restricts the gateway to the cached index and never touches the network.
+	Offline bool
+	// Timeout bounds each request for the release index, and how long a download may
+	// wait for the response or go without receiving data. Zero means no timeout.
+	Timeout time.Duration
+	// Retries is how many times a GET is retried after a connection error or a 408,
+	// 429 or 5xx response. An interrupted download is resumed as many times.
+	Retries int
+	// RetryWait is the base delay between retries. It doubles on each attempt and is
+	// jittered, unless the server asks for a delay with Retry-After.
+	RetryWait time.Duration
 }
 
+// maxSignatureSize bounds the signature read by GetSignature; armored detached
+// signatures are well under 1 KiB.
+const maxSignatureSize = 64 * 1024
+
+// maxRetryWait caps the delay between retries, including the one asked for by
+// Retry-After.
+const maxRetryWait = 30 * time.Second
+
+// ErrOffline is returned when a resource is requested in offline mode and no
+// cached copy is available.
+var ErrOffline = errors.New("not available offline")
+
+// errInterrupted marks a download that failed while receiving the archive, which
+// can be resumed.
+var errInterrupted = errors.New("download interrupted")
+
 type httpClient struct {
-	config *HttpConfig
-	client *http.Client
+	config    *HttpConfig
+	client    *http.Client
+	downloads *http.Client
+	index     []domain.VersionResponse
+}
+
+type indexCache struct {
+	// URL is where the index was fetched from. A cache of another URL is ignored, so
+	// switching to another mirror or index takes effect right away.
+	URL          string                   `json:"url"`
+	ETag         string                   `json:"etag"`
+	LastModified string                   `json:"last_modified"`
+	FetchedAt    time.Time                `json:"fetched_at"`
+	Versions     []domain.VersionResponse `json:"versions"`
 }
 
 func NewHttpGateway(config *HttpConfig) HttpGateway {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = config.Timeout
+
 	return &httpClient{
-		client: http.DefaultClient,
-		config: config,
+		// The timeout of the client covers reading the body, so downloads use a
+		// client without one and rely on the idle timeout of DownloadVersion.
+		client:    &http.Client{Transport: transport, Timeout: config.Timeout},
+		downloads: &http.Client{Transport: transport},
+		config:    config,
+	}
+}
+
+func (r *httpClient) GetVersions(ctx context.Context, platform domain.Platform) (domain.VersionsResponse, error) {
+	versions, err := r.getIndex(ctx)
+	if err != nil {
+		return domain.VersionsResponse{}, err
 	}
+
+	compatibleVersions := make([]domain.VersionResponse, 0, len(versions))
+
+	for _, v := range versions {
+		if v.IsCompatible(platform) {
+			compatibleVersions = append(compatibleVersions, v)
+		}
+	}
+
+	return domain.VersionsResponse{
+		Versions: compatibleVersions,
+	}, nil
 }
 
-func (r *httpClient) GetVersions(ctx context.Context) (domain.VersionsResponse, error) {
+// getIndex returns the release index, fetching it at most once per run. A
+// cached copy younger than IndexTTL is used as is, an older one is revalidated
+// with the server and used as a fallback when the server can't be reached.
+func (r *httpClient) getIndex(ctx context.Context) ([]domain.VersionResponse, error) {
+	if r.index != nil {
+		return r.index, nil
+	}
+
+	cached := r.readIndexCache(ctx)
+
+	if r.config.Offline {
+		if cached == nil {
+			slog.ErrorContext(ctx, "No cached index available offline", slog.String("GoDevClient", "GetVersions"))
+			return nil, ErrOffline
+		}
+		r.index = cached.Versions
+		return r.index, nil
+	}
+
+	if cached != nil && time.Since(cached.FetchedAt) < r.config.IndexTTL {
+		r.index = cached.Versions
+		return r.index, nil
+	}
+
+	fetched, err := r.fetchIndex(ctx, cached)
+	if err != nil {
+		if cached == nil {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "Using stale cached index", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
+		r.index = cached.Versions
+		return r.index, nil
+	}
+
+	r.writeIndexCache(ctx, fetched)
+	r.index = fetched.Versions
+	return r.index, nil
+}
+
+func (r *httpClient) fetchIndex(ctx context.Context, cached *indexCache) (*indexCache, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.config.GoVersionURL, nil)
 	if err != nil {
 		slog.ErrorContext(ctx, "Error while creating request", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
-		return domain.VersionsResponse{}, err
+		return nil, err
 	}
 
-	resp, err := r.client.Do(req)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := r.get(ctx, r.client, req)
 	if err != nil {
 		slog.ErrorContext(ctx, "Error while making request", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
-		return domain.VersionsResponse{}, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		cached.FetchedAt = time.Now()
+		return cached, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		slog.ErrorContext(ctx, "Unexpected status code", slog.String("GoDevClient", "GetVersions"), slog.String("status", resp.Status))
-		return domain.VersionsResponse{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	var versions []domain.VersionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
 		slog.ErrorContext(ctx, "Error decoding body", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
-		return domain.VersionsResponse{}, err
+		return nil, err
 	}
 
-	compatibleVersions := make([]domain.VersionResponse, 0, len(versions))
+	return &indexCache{
+		URL:          r.config.GoVersionURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+		Versions:     versions,
+	}, nil
+}
 
-	for _, v := range versions {
-		if v.IsCompatible() && v.Stable {
-			compatibleVersions = append(compatibleVersions, v)
+func (r *httpClient) readIndexCache(ctx context.Context) *indexCache {
+	if r.config.IndexCacheFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.config.IndexCacheFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.WarnContext(ctx, "Error reading cached index", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
 		}
+		return nil
 	}
 
-	return domain.VersionsResponse{
-		Versions: compatibleVersions,
-	}, nil
+	var cached indexCache
+	if err := json.Unmarshal(data, &cached); err != nil {
+		slog.WarnContext(ctx, "Error decoding cached index", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
+		return nil
+	}
+
+	if cached.URL != r.config.GoVersionURL {
+		slog.InfoContext(ctx, "Ignoring cached index of another URL", slog.String("GoDevClient", "GetVersions"), slog.String("url", cached.URL))
+		return nil
+	}
+
+	return &cached
 }
 
-func (r *httpClient) GetChecksum(ctx context.Context, version string) (string, error) {
-	res, err := r.GetVersions(ctx)
+// writeIndexCache stores the index for later runs. Failing to do so is not
+// fatal, the index is simply fetched again next time.
+func (r *httpClient) writeIndexCache(ctx context.Context, cached *indexCache) {
+	if r.config.IndexCacheFile == "" {
+		return
+	}
+
+	data, err := json.Marshal(cached)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(r.config.IndexCacheFile), 0755)
+	}
+	if err == nil {
+		tmp := r.config.IndexCacheFile + ".tmp"
+		if err = os.WriteFile(tmp, data, 0644); err == nil {
+			err = os.Rename(tmp, r.config.IndexCacheFile)
+		}
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Error writing cached index", slog.String("GoDevClient", "GetVersions"), slog.String("error", err.Error()))
+	}
+}
+
+func (r *httpClient) GetChecksum(ctx context.Context, version string, platform domain.Platform) (string, error) {
+	res, err := r.GetVersions(ctx, platform)
 	if err != nil {
 		return "", err
 	}
@@ -84,7 +261,7 @@ func (r *httpClient) GetChecksum(ctx context.Context, version string) (string, e
 	for _, v := range res.Versions {
 		if v.Version == version {
 			for _, f := range v.Files {
-				if f.Kind == "archive" && f.OS == runtime.GOOS && f.Arch == runtime.GOARCH {
+				if f.Kind == "archive" && f.OS == platform.OS && f.Arch == platform.Arch {
 					return f.SHA256, nil
 				}
 			}
@@ -94,14 +271,14 @@ func (r *httpClient) GetChecksum(ctx context.Context, version string) (string, e
 	return "", fmt.Errorf("version %s not found", version)
 }
 
-func (r *httpClient) VersionExists(ctx context.Context, version string) (bool, error) {
-	res, err := r.GetVersions(ctx)
+func (r *httpClient) VersionExists(ctx context.Context, version string, platform domain.Platform) (bool, error) {
+	res, err := r.GetVersions(ctx, platform)
 	if err != nil {
 		return false, err
 	}
 
 	for _, v := range res.Versions {
-		if v.Version == version && v.IsCompatible() && v.Stable {
+		if v.Version == version {
 			return true, nil
 		}
 	}
@@ -109,23 +286,308 @@ func (r *httpClient) VersionExists(ctx context.Context, version string) (bool, e
 	return false, err
 }
 
-func (r *httpClient) DownloadVersion(ctx context.Context, action *domain.Action, file *os.File) error {
-	resp, err := r.client.Get(fmt.Sprintf(r.config.GoDownloadURL, action.Filename()))
+// GetSignature fetches the armored detached signature published next to the archive
+// of action, as <archive>.asc.
+func (r *httpClient) GetSignature(ctx context.Context, action *domain.Action) ([]byte, error) {
+	if r.config.Offline {
+		slog.ErrorContext(ctx, "Signature not available offline", slog.String("GoDevClient", "GetSignature"), slog.String("file", action.Filename()))
+		return nil, ErrOffline
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(r.config.GoDownloadURL, action.Filename()+".asc"), nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error while creating request", slog.String("GoDevClient", "GetSignature"), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	resp, err := r.get(ctx, r.client, req)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error while making request", slog.String("GoDevClient", "GetSignature"), slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "Unexpected status code", slog.String("GoDevClient", "GetSignature"), slog.String("status", resp.Status))
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	signature, err := io.ReadAll(io.LimitReader(resp.Body, maxSignatureSize))
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading body", slog.String("GoDevClient", "GetSignature"), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return signature, nil
+}
+
+// DownloadVersion writes the archive of action to file, calling progress, when not nil,
+// as it is received. When file already holds the beginning of the archive, the rest is
+// requested with a Range header; servers that ignore it send the whole archive, which
+// replaces the content of file. The total reported to progress is the size of the
+// archive, or -1 when unknown. A download interrupted by a connection error, or by
+// receiving no data for Timeout, is resumed up to Retries times.
+func (r *httpClient) DownloadVersion(ctx context.Context, action *domain.Action, file *os.File, progress func(written int64, total int64)) error {
+	return r.DownloadURL(ctx, fmt.Sprintf(r.config.GoDownloadURL, action.Filename()), file, progress)
+}
+
+// StreamVersion writes the archive of action to writer as it is received, the way
+// DownloadVersion does. What was written can't be taken back, so an interrupted
+// download is only resumed when the server honours the Range header.
+func (r *httpClient) StreamVersion(ctx context.Context, action *domain.Action, writer io.Writer, progress func(written int64, total int64)) error {
+	return r.fetch(ctx, fmt.Sprintf(r.config.GoDownloadURL, action.Filename()), &streamTarget{writer: writer}, progress)
+}
+
+// DownloadURL writes the file at url to file, the way DownloadVersion does.
+func (r *httpClient) DownloadURL(ctx context.Context, url string, file *os.File, progress func(written int64, total int64)) error {
+	return r.fetch(ctx, url, fileTarget{file: file}, progress)
+}
+
+// downloadTarget is where a download is written.
+type downloadTarget interface {
+	io.Writer
+	// offset is the size already written, which is requested no more.
+	offset() (int64, error)
+	// restart discards what was written, for a server that sends the whole file again.
+	restart() error
+}
+
+type fileTarget struct {
+	file *os.File
+}
+
+func (t fileTarget) Write(p []byte) (int, error) {
+	return t.file.Write(p)
+}
+
+func (t fileTarget) offset() (int64, error) {
+	return t.file.Seek(0, io.SeekEnd)
+}
+
+func (t fileTarget) restart() error {
+	if err := t.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := t.file.Seek(0, io.SeekStart)
+	return err
+}
+
+type streamTarget struct {
+	writer  io.Writer
+	written int64
+}
+
+func (t *streamTarget) Write(p []byte) (int, error) {
+	n, err := t.writer.Write(p)
+	t.written += int64(n)
+	return n, err
+}
+
+func (t *streamTarget) offset() (int64, error) {
+	return t.written, nil
+}
+
+func (t *streamTarget) restart() error {
+	return errors.New("server does not support resuming a streamed download")
+}
+
+func (r *httpClient) fetch(ctx context.Context, url string, target downloadTarget, progress func(written int64, total int64)) error {
+	if r.config.Offline {
+		slog.ErrorContext(ctx, "Archive not available offline", slog.String("GoDevClient", "DownloadVersion"), slog.String("url", url))
+		return ErrOffline
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := r.download(ctx, url, target, progress)
+		if err == nil || !errors.Is(err, errInterrupted) || attempt >= r.config.Retries || ctx.Err() != nil {
+			return err
+		}
+
+		slog.WarnContext(ctx, "Resuming interrupted download", slog.String("GoDevClient", "DownloadVersion"), slog.Int("attempt", attempt+1), slog.String("error", err.Error()))
+		if err := sleep(ctx, r.retryWait(attempt, nil)); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *httpClient) download(ctx context.Context, url string, target downloadTarget, progress func(written int64, total int64)) error {
+	offset, err := target.offset()
+	if err != nil {
+		slog.ErrorContext(ctx, "Error while seeking file", slog.String("GoDevClient", "DownloadVersion"), slog.String("error", err.Error()))
+		return err
+	}
+
+	downloadCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	req, err := http.NewRequestWithContext(downloadCtx, http.MethodGet, url, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error while creating request", slog.String("GoDevClient", "DownloadVersion"), slog.String("error", err.Error()))
+		return err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := r.get(ctx, r.downloads, req)
 	if err != nil {
 		slog.ErrorContext(ctx, "Error while downloading file", slog.String("GoDevClient", "DownloadVersion"), slog.String("error", err.Error()))
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	total := resp.ContentLength
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			slog.ErrorContext(ctx, "Unexpected content range", slog.String("GoDevClient", "DownloadVersion"), slog.String("range", resp.Header.Get("Content-Range")))
+			return fmt.Errorf("unexpected content range: %s", resp.Header.Get("Content-Range"))
+		}
+		slog.InfoContext(ctx, "Resuming download", slog.String("GoDevClient", "DownloadVersion"), slog.Int64("offset", offset))
+		if total >= 0 {
+			total += offset
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		slog.InfoContext(ctx, "Download already complete", slog.String("GoDevClient", "DownloadVersion"), slog.Int64("size", offset))
+		return nil
+	case resp.StatusCode == http.StatusOK:
+		if offset > 0 {
+			slog.InfoContext(ctx, "Server does not support resuming, restarting download", slog.String("GoDevClient", "DownloadVersion"))
+			if err := target.restart(); err != nil {
+				slog.ErrorContext(ctx, "Error while restarting download", slog.String("GoDevClient", "DownloadVersion"), slog.String("error", err.Error()))
+				return err
+			}
+			offset = 0
+		}
+	default:
 		slog.ErrorContext(ctx, "Unexpected status code", slog.String("GoDevClient", "DownloadVersion"), slog.String("status", resp.Status))
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	if _, err := io.Copy(file, resp.Body); err != nil {
+	body := &progressReader{reader: resp.Body, written: offset, total: total, progress: progress}
+
+	if r.config.Timeout > 0 {
+		stalled := fmt.Errorf("no data received for %s", r.config.Timeout)
+		body.idle = time.AfterFunc(r.config.Timeout, func() { cancel(stalled) })
+		body.timeout = r.config.Timeout
+		defer body.idle.Stop()
+	}
+
+	if _, err := io.Copy(target, body); err != nil {
+		if cause := context.Cause(downloadCtx); cause != nil && ctx.Err() == nil {
+			err = fmt.Errorf("%w: %w", errInterrupted, cause)
+		}
 		slog.ErrorContext(ctx, "Error while copying file", slog.String("GoDevClient", "DownloadVersion"), slog.String("error", err.Error()))
 		return err
 	}
 
 	return nil
 }
+
+// get sends req with client, retrying connection errors and 408, 429 and 5xx
+// responses up to Retries times.
+func (r *httpClient) get(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req.Clone(req.Context()))
+		if attempt >= r.config.Retries || ctx.Err() != nil || !retryable(resp, err) {
+			return resp, err
+		}
+
+		if err != nil {
+			slog.WarnContext(ctx, "Retrying request", slog.String("url", req.URL.String()), slog.Int("attempt", attempt+1), slog.String("error", err.Error()))
+		} else {
+			slog.WarnContext(ctx, "Retrying request", slog.String("url", req.URL.String()), slog.Int("attempt", attempt+1), slog.String("status", resp.Status))
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, r.retryWait(attempt, resp)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	default:
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+}
+
+// retryWait is the delay before retrying after attempt: the one asked for by the
+// Retry-After header of resp, if any, or RetryWait doubled on each attempt with
+// jitter. Both are capped at maxRetryWait.
+func (r *httpClient) retryWait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(wait, maxRetryWait)
+		}
+	}
+
+	wait := min(r.config.RetryWait<<attempt, maxRetryWait)
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as a date.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// progressReader reports the bytes read so far from reader to progress, when not
+// nil, and pushes back the idle timer on each read.
+type progressReader struct {
+	reader   io.Reader
+	written  int64
+	total    int64
+	progress func(written int64, total int64)
+	idle     *time.Timer
+	timeout  time.Duration
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.written += int64(n)
+		if r.idle != nil {
+			r.idle.Reset(r.timeout)
+		}
+		if r.progress != nil {
+			r.progress(r.written, r.total)
+		}
+	}
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w: %w", errInterrupted, err)
+	}
+	return n, err
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"io"
 	"os"
 
 	"github.com/sbonaiva/govm/internal/domain"
@@ -12,8 +13,8 @@ type HttpGatewayMock struct {
 	mock.Mock
 }
 
-func (m *HttpGatewayMock) GetVersions(ctx context.Context) (domain.VersionsResponse, error) {
-	args := m.Called(ctx)
+func (m *HttpGatewayMock) GetVersions(ctx context.Context, platform domain.Platform) (domain.VersionsResponse, error) {
+	args := m.Called(ctx, platform)
 	return args.Get(0).(domain.VersionsResponse), args.Error(1)
 }
 
@@ -22,17 +23,32 @@ func (m *HttpGatewayMock) GetVersionsList(ctx context.Context) ([]string, error)
 	return args.Get(0).([]string), args.Error(1)
 }
 
-func (m *HttpGatewayMock) GetChecksum(ctx context.Context, version string) (string, error) {
-	args := m.Called(ctx, version)
+func (m *HttpGatewayMock) GetChecksum(ctx context.Context, version string, platform domain.Platform) (string, error) {
+	args := m.Called(ctx, version, platform)
 	return args.String(0), args.Error(1)
 }
 
-func (m *HttpGatewayMock) VersionExists(ctx context.Context, version string) (bool, error) {
-	args := m.Called(ctx, version)
+func (m *HttpGatewayMock) VersionExists(ctx context.Context, version string, platform domain.Platform) (bool, error) {
+	args := m.Called(ctx, version, platform)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *HttpGatewayMock) DownloadVersion(ctx context.Context, action *domain.Action, file *os.File) error {
-	args := m.Called(ctx, action, file)
+func (m *HttpGatewayMock) DownloadVersion(ctx context.Context, action *domain.Action, file *os.File, progress func(written int64, total int64)) error {
+	args := m.Called(ctx, action, file, progress)
 	return args.Error(0)
 }
+
+func (m *HttpGatewayMock) StreamVersion(ctx context.Context, action *domain.Action, writer io.Writer, progress func(written int64, total int64)) error {
+	args := m.Called(ctx, action, writer, progress)
+	return args.Error(0)
+}
+
+func (m *HttpGatewayMock) DownloadURL(ctx context.Context, url string, file *os.File, progress func(written int64, total int64)) error {
+	args := m.Called(ctx, url, file, progress)
+	return args.Error(0)
+}
+
+func (m *HttpGatewayMock) GetSignature(ctx context.Context, action *domain.Action) ([]byte, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).([]byte), args.Error(1)
+}
@@ -1,13 +1,18 @@
 package gateway_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
@@ -44,7 +49,7 @@ func TestGetVersionsSuccess(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	result, err := gatewayInstance.GetVersions(context.Background())
+	result, err := gatewayInstance.GetVersions(context.Background(), domain.HostPlatform())
 
 	// Assert
 	assert.NoError(t, err)
@@ -53,6 +58,66 @@ func TestGetVersionsSuccess(t *testing.T) {
 	assert.Len(t, result.Versions[0].Files, 4)
 }
 
+func TestGetVersionsIncludesUnstable(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		files := []domain.FileResponse{{Kind: "archive", OS: runtime.GOOS, Arch: runtime.GOARCH}}
+		versions := []domain.VersionResponse{
+			{Version: "go1.23rc1", Stable: false, Files: files},
+			{Version: "go1.22.5", Stable: true, Files: files},
+			{Version: "go1.22.4", Stable: true},
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(versions)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	gatewayInstance := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: server.URL})
+
+	// Act
+	result, err := gatewayInstance.GetVersions(context.Background(), domain.HostPlatform())
+	exists, existsErr := gatewayInstance.VersionExists(context.Background(), "go1.23rc1", domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go1.23rc1", "go1.22.5"}, result.StringSlice())
+	assert.NoError(t, existsErr)
+	assert.True(t, exists)
+}
+
+func TestGetVersionsForPlatform(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versions := []domain.VersionResponse{
+			{Version: "go1.22.5", Stable: true, Files: []domain.FileResponse{{Kind: "archive", OS: "linux", Arch: "arm64", SHA256: "arm64checksum"}}},
+			{Version: "go1.22.4", Stable: true, Files: []domain.FileResponse{{Kind: "archive", OS: "linux", Arch: "amd64", SHA256: "amd64checksum"}}},
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(versions)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	gatewayInstance := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: server.URL})
+	platform := domain.Platform{OS: "linux", Arch: "arm64"}
+
+	// Act
+	result, err := gatewayInstance.GetVersions(context.Background(), platform)
+	checksum, checksumErr := gatewayInstance.GetChecksum(context.Background(), "go1.22.5", platform)
+	exists, existsErr := gatewayInstance.VersionExists(context.Background(), "go1.22.4", platform)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go1.22.5"}, result.StringSlice())
+	assert.NoError(t, checksumErr)
+	assert.Equal(t, "arm64checksum", checksum)
+	assert.NoError(t, existsErr)
+	assert.False(t, exists)
+}
+
 func TestGetVersionsErrorCreatingRequest(t *testing.T) {
 	// Arrange
 	config := &gateway.HttpConfig{
@@ -61,7 +126,7 @@ func TestGetVersionsErrorCreatingRequest(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	_, err := gatewayInstance.GetVersions(context.Background())
+	_, err := gatewayInstance.GetVersions(context.Background(), domain.HostPlatform())
 
 	// Assert
 	assert.Error(t, err)
@@ -85,7 +150,7 @@ func TestGetVersionsErrorUnexpectedStatusCode(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	_, err := gatewayInstance.GetVersions(context.Background())
+	_, err := gatewayInstance.GetVersions(context.Background(), domain.HostPlatform())
 
 	// Assert
 	assert.Error(t, err)
@@ -111,7 +176,7 @@ func TestGetVersionsErrorDecode(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	_, err := gatewayInstance.GetVersions(context.Background())
+	_, err := gatewayInstance.GetVersions(context.Background(), domain.HostPlatform())
 
 	// Assert
 	assert.Error(t, err)
@@ -148,7 +213,7 @@ func TestGetChecksumVersionFound(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	result, err := gatewayInstance.GetChecksum(context.Background(), "1.17")
+	result, err := gatewayInstance.GetChecksum(context.Background(), "1.17", domain.HostPlatform())
 
 	// Assert
 	assert.NoError(t, err)
@@ -185,7 +250,7 @@ func TestGetChecksumVersionNotFound(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	result, err := gatewayInstance.GetChecksum(context.Background(), "1.17")
+	result, err := gatewayInstance.GetChecksum(context.Background(), "1.17", domain.HostPlatform())
 
 	// Assert
 	assert.Error(t, err)
@@ -210,7 +275,7 @@ func TestGetChecksumUnexpectedStatusCode(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	result, err := gatewayInstance.GetChecksum(context.Background(), "1.17")
+	result, err := gatewayInstance.GetChecksum(context.Background(), "1.17", domain.HostPlatform())
 
 	// Assert
 	assert.Error(t, err)
@@ -248,7 +313,7 @@ func TestVersionExistsVersionFound(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	exists, err := gatewayInstance.VersionExists(context.Background(), "1.17")
+	exists, err := gatewayInstance.VersionExists(context.Background(), "1.17", domain.HostPlatform())
 
 	// Assert
 	assert.NoError(t, err)
@@ -285,7 +350,7 @@ func TestVersionExistsVersionNotFound(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	exists, err := gatewayInstance.VersionExists(context.Background(), "1.17")
+	exists, err := gatewayInstance.VersionExists(context.Background(), "1.17", domain.HostPlatform())
 
 	// Assert
 	assert.NoError(t, err)
@@ -309,7 +374,7 @@ func TestVersionExistsUnexpectedStatusCode(t *testing.T) {
 	gatewayInstance := gateway.NewHttpGateway(config)
 
 	// Act
-	exists, err := gatewayInstance.VersionExists(context.Background(), "1.17")
+	exists, err := gatewayInstance.VersionExists(context.Background(), "1.17", domain.HostPlatform())
 
 	// Assert
 	assert.Error(t, err)
@@ -339,7 +404,108 @@ func TestDownloadVersion_Success(t *testing.T) {
 	defer os.Remove(file.Name())
 
 	// Act
-	err = gatewayInstance.DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file)
+	err = gatewayInstance.DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	fileContent, _ := os.ReadFile(file.Name())
+	assert.Equal(t, "file content", string(fileContent))
+}
+
+func TestDownloadVersion_Progress(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "12")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "file content")
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{
+		GoVersionURL:  server.URL,
+		GoDownloadURL: server.URL + "/%s",
+	}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	var written, total int64
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, func(w int64, t int64) {
+		written, total = w, t
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), written)
+	assert.Equal(t, int64(12), total)
+}
+
+func TestDownloadVersion_Resume(t *testing.T) {
+	// Arrange
+	var rangeHeader string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader = r.Header.Get("Range")
+		http.ServeContent(w, r, "go1.22.3.linux-amd64.tar.gz", time.Time{}, strings.NewReader("file content"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{
+		GoVersionURL:  server.URL,
+		GoDownloadURL: server.URL + "/%s",
+	}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("file ")
+	assert.NoError(t, err)
+
+	var written, total int64
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, func(w int64, t int64) {
+		written, total = w, t
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "bytes=5-", rangeHeader)
+	fileContent, _ := os.ReadFile(file.Name())
+	assert.Equal(t, "file content", string(fileContent))
+	assert.Equal(t, int64(12), written)
+	assert.Equal(t, int64(12), total)
+}
+
+func TestDownloadVersion_ResumeNotSupported(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "file content")
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{
+		GoVersionURL:  server.URL,
+		GoDownloadURL: server.URL + "/%s",
+	}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("stale partial content")
+	assert.NoError(t, err)
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
 
 	// Assert
 	assert.NoError(t, err)
@@ -347,6 +513,65 @@ func TestDownloadVersion_Success(t *testing.T) {
 	assert.Equal(t, "file content", string(fileContent))
 }
 
+func TestDownloadVersion_ResumeAlreadyComplete(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "go1.22.3.linux-amd64.tar.gz", time.Time{}, strings.NewReader("file content"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{
+		GoVersionURL:  server.URL,
+		GoDownloadURL: server.URL + "/%s",
+	}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("file content")
+	assert.NoError(t, err)
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	fileContent, _ := os.ReadFile(file.Name())
+	assert.Equal(t, "file content", string(fileContent))
+}
+
+func TestDownloadVersion_ResumeUnexpectedRange(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "bytes 0-11/12")
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, "file content")
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{
+		GoVersionURL:  server.URL,
+		GoDownloadURL: server.URL + "/%s",
+	}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("file ")
+	assert.NoError(t, err)
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "unexpected content range: bytes 0-11/12", err.Error())
+}
+
 func TestDownloadVersion_ErrorDownloading(t *testing.T) {
 	// Arrange
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -368,9 +593,599 @@ func TestDownloadVersion_ErrorDownloading(t *testing.T) {
 	defer os.Remove(file.Name())
 
 	// Act
-	err = gatewayInstance.DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file)
+	err = gatewayInstance.DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "unexpected status code: 404", err.Error())
+}
+
+func indexVersions() []domain.VersionResponse {
+	return []domain.VersionResponse{
+		{
+			Version: "go1.22.3",
+			Stable:  true,
+			Files: []domain.FileResponse{
+				{Kind: "archive", OS: runtime.GOOS, Arch: runtime.GOARCH, SHA256: "dummychecksum"},
+			},
+		},
+	}
+}
+
+func newIndexServer(t *testing.T, requests *int, etag string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if etag != "" && r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(indexVersions())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetVersionsFetchesIndexOncePerRun(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := newIndexServer(t, &requests, "")
+	gatewayInstance := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: server.URL})
+	ctx := context.Background()
+
+	// Act
+	_, errVersions := gatewayInstance.GetVersions(ctx, domain.HostPlatform())
+	exists, errExists := gatewayInstance.VersionExists(ctx, "go1.22.3", domain.HostPlatform())
+	checksum, errChecksum := gatewayInstance.GetChecksum(ctx, "go1.22.3", domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, errVersions)
+	assert.NoError(t, errExists)
+	assert.NoError(t, errChecksum)
+	assert.True(t, exists)
+	assert.Equal(t, "dummychecksum", checksum)
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetVersionsWritesAndReusesIndexCache(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := newIndexServer(t, &requests, "")
+	cacheFile := filepath.Join(t.TempDir(), "cache", "index.json")
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, IndexCacheFile: cacheFile, IndexTTL: time.Hour}
+
+	// Act
+	_, errFirst := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+	result, errSecond := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, errFirst)
+	assert.NoError(t, errSecond)
+	assert.FileExists(t, cacheFile)
+	assert.Len(t, result.Versions, 1)
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetVersionsRevalidatesExpiredIndexCache(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := newIndexServer(t, &requests, `"v1"`)
+	cacheFile := filepath.Join(t.TempDir(), "index.json")
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, IndexCacheFile: cacheFile}
+
+	// Act
+	_, errFirst := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+	result, errSecond := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, errFirst)
+	assert.NoError(t, errSecond)
+	assert.Len(t, result.Versions, 1)
+	assert.Equal(t, 2, requests)
+}
+
+func TestGetVersionsFallsBackToStaleIndexCache(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := newIndexServer(t, &requests, "")
+	cacheFile := filepath.Join(t.TempDir(), "index.json")
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, IndexCacheFile: cacheFile}
+	_, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+	assert.NoError(t, err)
+	server.Close()
+
+	// Act
+	result, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Versions, 1)
+}
+
+func TestGetVersionsOfflineUsesIndexCache(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := newIndexServer(t, &requests, "")
+	cacheFile := filepath.Join(t.TempDir(), "index.json")
+	_, err := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: server.URL, IndexCacheFile: cacheFile}).GetVersions(context.Background(), domain.HostPlatform())
+	assert.NoError(t, err)
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, IndexCacheFile: cacheFile, Offline: true}
+
+	// Act
+	result, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Versions, 1)
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetVersionsIgnoresIndexCacheOfAnotherURL(t *testing.T) {
+	// Arrange
+	oldRequests := 0
+	oldServer := newIndexServer(t, &oldRequests, `"v1"`)
+	cacheFile := filepath.Join(t.TempDir(), "index.json")
+	_, err := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: oldServer.URL, IndexCacheFile: cacheFile, IndexTTL: time.Hour}).GetVersions(context.Background(), domain.HostPlatform())
+	assert.NoError(t, err)
+
+	var ifNoneMatch string
+	newRequests := 0
+	newServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newRequests++
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		json.NewEncoder(w).Encode(indexVersions())
+	}))
+	t.Cleanup(newServer.Close)
+	config := &gateway.HttpConfig{GoVersionURL: newServer.URL, IndexCacheFile: cacheFile, IndexTTL: time.Hour}
+
+	// Act
+	result, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Versions, 1)
+	assert.Equal(t, 1, newRequests)
+	assert.Empty(t, ifNoneMatch)
+}
+
+func TestGetVersionsDoesNotFallBackToIndexCacheOfAnotherURL(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := newIndexServer(t, &requests, "")
+	cacheFile := filepath.Join(t.TempDir(), "index.json")
+	_, err := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: server.URL, IndexCacheFile: cacheFile}).GetVersions(context.Background(), domain.HostPlatform())
+	assert.NoError(t, err)
+
+	// Act
+	_, errOnline := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: "http://127.0.0.1:0", IndexCacheFile: cacheFile}).GetVersions(context.Background(), domain.HostPlatform())
+	_, errOffline := gateway.NewHttpGateway(&gateway.HttpConfig{GoVersionURL: "http://127.0.0.1:0", IndexCacheFile: cacheFile, Offline: true}).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.Error(t, errOnline)
+	assert.ErrorIs(t, errOffline, gateway.ErrOffline)
+}
+
+func TestGetVersionsOfflineWithoutIndexCache(t *testing.T) {
+	// Arrange
+	config := &gateway.HttpConfig{
+		GoVersionURL:   "http://127.0.0.1:0",
+		IndexCacheFile: filepath.Join(t.TempDir(), "index.json"),
+		Offline:        true,
+	}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.ErrorIs(t, err, gateway.ErrOffline)
+}
+
+func TestDownloadVersionOffline(t *testing.T) {
+	// Arrange
+	config := &gateway.HttpConfig{GoDownloadURL: "http://127.0.0.1:0/%s", Offline: true}
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "go1.22.3"}, file, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, gateway.ErrOffline)
+}
+
+func TestGetVersionsRetriesServerErrors(t *testing.T) {
+	// Arrange
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(indexVersions())
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, Retries: 2, RetryWait: time.Millisecond}
+
+	// Act
+	result, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Versions, 1)
+	assert.Equal(t, 3, requests)
+}
+
+func TestGetVersionsRetriesExhausted(t *testing.T) {
+	// Arrange
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, Retries: 2, RetryWait: time.Millisecond}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "unexpected status code: 502", err.Error())
+	assert.Equal(t, 3, requests)
+}
+
+func TestGetVersionsDoesNotRetryClientErrors(t *testing.T) {
+	// Arrange
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, Retries: 2, RetryWait: time.Millisecond}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetVersionsHonorsRetryAfter(t *testing.T) {
+	// Arrange
+	var requested []time.Time
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, time.Now())
+		if len(requested) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(indexVersions())
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, Retries: 1, RetryWait: time.Millisecond}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, requested, 2)
+	assert.GreaterOrEqual(t, requested[1].Sub(requested[0]), time.Second)
+}
+
+func TestGetVersionsTimeout(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoVersionURL: server.URL, Timeout: 50 * time.Millisecond}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetVersions(context.Background(), domain.HostPlatform())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout")
+}
+
+func TestDownloadVersion_ResumesInterruptedDownload(t *testing.T) {
+	// Arrange
+	var ranges []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			w.Header().Set("Content-Length", "12")
+			w.Write([]byte("file "))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "go1.22.3.linux-amd64.tar.gz", time.Time{}, strings.NewReader("file content"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s", Retries: 1, RetryWait: time.Millisecond}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "bytes=5-"}, ranges)
+	fileContent, _ := os.ReadFile(file.Name())
+	assert.Equal(t, "file content", string(fileContent))
+}
+
+func TestDownloadVersion_ResumesStalledDownload(t *testing.T) {
+	// Arrange
+	var ranges []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			w.Header().Set("Content-Length", "12")
+			w.Write([]byte("file "))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		http.ServeContent(w, r, "go1.22.3.linux-amd64.tar.gz", time.Time{}, strings.NewReader("file content"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{
+		GoDownloadURL: server.URL + "/%s",
+		Timeout:       100 * time.Millisecond,
+		Retries:       1,
+		RetryWait:     time.Millisecond,
+	}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "bytes=5-"}, ranges)
+	fileContent, _ := os.ReadFile(file.Name())
+	assert.Equal(t, "file content", string(fileContent))
+}
+
+func TestDownloadVersion_StalledDownloadRetriesExhausted(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "12")
+		w.Write([]byte("file "))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s", Timeout: 50 * time.Millisecond}
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Act
+	err = gateway.NewHttpGateway(config).DownloadVersion(context.Background(), &domain.Action{Version: "1.17"}, file, nil)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no data received for 50ms")
+}
+
+func TestStreamVersion_Success(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "12")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "file content")
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s"}
+
+	var buf bytes.Buffer
+	var written, total int64
+
+	// Act
+	err := gateway.NewHttpGateway(config).StreamVersion(context.Background(), &domain.Action{Version: "1.17"}, &buf, func(w int64, t int64) {
+		written, total = w, t
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "file content", buf.String())
+	assert.Equal(t, int64(12), written)
+	assert.Equal(t, int64(12), total)
+}
+
+func TestStreamVersion_ResumesInterruptedDownload(t *testing.T) {
+	// Arrange
+	var ranges []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			w.Header().Set("Content-Length", "12")
+			w.Write([]byte("file "))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "go1.22.3.linux-amd64.tar.gz", time.Time{}, strings.NewReader("file content"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s", Retries: 1, RetryWait: time.Millisecond}
+
+	var buf bytes.Buffer
+
+	// Act
+	err := gateway.NewHttpGateway(config).StreamVersion(context.Background(), &domain.Action{Version: "1.17"}, &buf, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "bytes=5-"}, ranges)
+	assert.Equal(t, "file content", buf.String())
+}
+
+func TestStreamVersion_ResumeNotSupported(t *testing.T) {
+	// Arrange
+	var requests int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Length", "12")
+		w.WriteHeader(http.StatusOK)
+		if requests == 1 {
+			w.Write([]byte("file "))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		fmt.Fprint(w, "file content")
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s", Retries: 1, RetryWait: time.Millisecond}
+
+	var buf bytes.Buffer
+
+	// Act
+	err := gateway.NewHttpGateway(config).StreamVersion(context.Background(), &domain.Action{Version: "1.17"}, &buf, nil)
+
+	// Assert
+	assert.EqualError(t, err, "server does not support resuming a streamed download")
+	assert.Equal(t, "file ", buf.String())
+}
+
+func TestStreamVersionOffline(t *testing.T) {
+	// Arrange
+	config := &gateway.HttpConfig{GoDownloadURL: "http://localhost/%s", Offline: true}
+
+	// Act
+	err := gateway.NewHttpGateway(config).StreamVersion(context.Background(), &domain.Action{Version: "1.17"}, &bytes.Buffer{}, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, gateway.ErrOffline)
+}
+
+func TestGetSignatureSuccess(t *testing.T) {
+	// Arrange
+	var path string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte("-----BEGIN PGP SIGNATURE-----"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s"}
+
+	// Act
+	signature, err := gateway.NewHttpGateway(config).GetSignature(context.Background(), &domain.Action{Version: "go1.22.3"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN PGP SIGNATURE-----", string(signature))
+	assert.Equal(t, fmt.Sprintf("/go1.22.3.%s-%s.tar.gz.asc", runtime.GOOS, runtime.GOARCH), path)
+}
+
+func TestGetSignatureNotFound(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &gateway.HttpConfig{GoDownloadURL: server.URL + "/%s"}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetSignature(context.Background(), &domain.Action{Version: "go1.22.3"})
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, "unexpected status code: 404", err.Error())
 }
+
+func TestGetSignatureOffline(t *testing.T) {
+	// Arrange
+	config := &gateway.HttpConfig{GoDownloadURL: "http://127.0.0.1:0/%s", Offline: true}
+
+	// Act
+	_, err := gateway.NewHttpGateway(config).GetSignature(context.Background(), &domain.Action{Version: "go1.22.3"})
+
+	// Assert
+	assert.ErrorIs(t, err, gateway.ErrOffline)
+}
+
+func TestDownloadURLSuccess(t *testing.T) {
+	// Arrange
+	var path string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte("file content"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	file, err := os.CreateTemp("", "downloaded_file")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Act
+	err = gateway.NewHttpGateway(&gateway.HttpConfig{}).DownloadURL(context.Background(), server.URL+"/archives/go.tar.gz", file, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "/archives/go.tar.gz", path)
+	fileContent, _ := os.ReadFile(file.Name())
+	assert.Equal(t, "file content", string(fileContent))
+}
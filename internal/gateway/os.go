@@ -1,26 +1,51 @@
 package gateway
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"os/user"
+	"path/filepath"
 	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sbonaiva/govm/internal/domain"
 )
 
 type OsGateway interface {
 	GetUserHomeDir() (string, error)
+	GetWorkingDir() (string, error)
+	GetExecutable() (string, error)
 	Stat(path string) (os.FileInfo, error)
 	CreateDir(path string, perm os.FileMode) error
 	RemoveDir(path string) error
 	CreateFile(path string) (*os.File, error)
 	OpenFile(path string) (*os.File, error)
+	AppendFile(path string) (*os.File, error)
 	ReadFile(path string) ([]byte, error)
 	WriteFile(path string, data []byte, perm os.FileMode) error
 	RemoveFile(path string) error
+	Rename(source string, target string) error
+	ReadDir(path string) ([]os.DirEntry, error)
+	CreateSymlink(target string, link string) error
+	ReadSymlink(link string) (string, error)
 	GetEnv(key string) string
-	Untar(source string, target string) error
-	GetInstalledGoVersion() (string, error)
+	Untar(ctx context.Context, source string, target string, progress func(written int64, total int64)) error
+	UntarReader(ctx context.Context, reader io.Reader, target string) error
+	ReadArchiveFile(source string, name string) ([]byte, error)
+	ReadArchivePlatform(source string) (domain.Platform, error)
+	RunCommand(name string, args []string, env []string) (int, error)
+	FetchSource(ctx context.Context, repo string, ref string, target string) ([]byte, error)
+	BuildSource(ctx context.Context, goroot string, bootstrap string) ([]byte, error)
+	ReadConfig(path string) (domain.Config, error)
+	WriteConfig(path string, config domain.Config) error
 }
 
 type osClient struct{}
@@ -37,6 +62,18 @@ func (o *osClient) GetUserHomeDir() (string, error) {
 	return usr.HomeDir, nil
 }
 
+func (o *osClient) GetWorkingDir() (string, error) {
+	return os.Getwd()
+}
+
+func (o *osClient) GetExecutable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(executable)
+}
+
 func (o *osClient) Stat(path string) (os.FileInfo, error) {
 	return os.Stat(path)
 }
@@ -63,6 +100,20 @@ func (o *osClient) OpenFile(path string) (*os.File, error) {
 	return os.Open(path)
 }
 
+// AppendFile opens the file at path for writing, creating it if needed, positioned
+// at its end.
+func (o *osClient) AppendFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
 func (o *osClient) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
@@ -75,29 +126,409 @@ func (o *osClient) RemoveFile(path string) error {
 	return os.Remove(path)
 }
 
+func (o *osClient) Rename(source string, target string) error {
+	return os.Rename(source, target)
+}
+
+func (o *osClient) ReadDir(path string) ([]os.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
+func (o *osClient) CreateSymlink(target string, link string) error {
+	tmp := link + ".tmp"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, link)
+}
+
+func (o *osClient) ReadSymlink(link string) (string, error) {
+	return os.Readlink(link)
+}
+
 func (o *osClient) GetEnv(key string) string {
 	return os.Getenv(key)
 }
 
-func (o *osClient) Untar(source string, target string) error {
-	return exec.Command("tar", "-C", target, "-xzf", source).Run()
+// Untar extracts the archive source into target, see UntarReader. When progress is
+// not nil it is called with the bytes of source read so far and its size.
+func (o *osClient) Untar(ctx context.Context, source string, target string, progress func(written int64, total int64)) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	return o.UntarReader(ctx, &progressReader{reader: file, total: fi.Size(), progress: progress}, target)
 }
 
-func (o *osClient) GetInstalledGoVersion() (string, error) {
-	goPath, err := exec.LookPath("go")
+// UntarReader extracts the gzipped tarball read from reader into target, dropping the
+// top-level directory of every entry like tar --strip-components=1 does. Entries that
+// would land outside target, symlinks pointing outside of it and unsupported entry
+// types are rejected. Symlinks created by earlier entries are never followed, so they
+// can't be used to write outside target either. Extraction stops with the error of ctx when it is
+// done, leaving the entries extracted so far in target.
+func (o *osClient) UntarReader(ctx context.Context, reader io.Reader, target string) error {
+	root, err := filepath.Abs(target)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	outputBytes, err := exec.Command(goPath, "version").CombinedOutput()
+	gz, err := gzip.NewReader(reader)
 	if err != nil {
-		return "", err
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := stripComponent(header.Name)
+		if name == "" {
+			continue
+		}
+
+		path, err := archivePath(root, name)
+		if err != nil {
+			return err
+		}
+		if err := checkNoSymlinks(root, filepath.Dir(path)); err != nil {
+			return fmt.Errorf("archive entry %q: %w", header.Name, err)
+		}
+
+		mode := header.FileInfo().Mode().Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("archive entry %q: %s is a symlink", header.Name, path)
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			if err := os.Chmod(path, mode|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tr, path, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Cleaned, ".." only ever leads the link, so following it can't climb out
+			// of a directory reached through another link.
+			linkname := filepath.Clean(filepath.FromSlash(header.Linkname))
+			if filepath.IsAbs(linkname) {
+				return fmt.Errorf("archive entry %q links to absolute path %q", header.Name, header.Linkname)
+			}
+			if !within(root, filepath.Join(filepath.Dir(path), linkname)) {
+				return fmt.Errorf("archive entry %q links outside of the target directory", header.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if err := os.Symlink(linkname, path); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			linkPath, err := archivePath(root, stripComponent(header.Linkname))
+			if err != nil {
+				return err
+			}
+			if err := checkNoSymlinks(root, filepath.Dir(linkPath)); err != nil {
+				return fmt.Errorf("archive entry %q: %w", header.Name, err)
+			}
+			if fi, err := os.Lstat(linkPath); err != nil || !fi.Mode().IsRegular() {
+				return fmt.Errorf("archive entry %q links to %q, which is not a regular file", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if err := os.Link(linkPath, path); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return fmt.Errorf("archive entry %q has unsupported type %q", header.Name, header.Typeflag)
+		}
 	}
+}
 
-	outputParts := strings.Split(string(outputBytes), " ")
-	if len(outputParts) < 3 {
-		return "", errors.New("unexpected go version command output")
+func extractFile(reader io.Reader, path string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
 	}
 
-	return outputParts[2], nil
+	// The entry replaces whatever is at path, rather than writing through a symlink or
+	// a hard link to another file.
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, mode)
+}
+
+// maxArchiveFileSize bounds the content returned by ReadArchiveFile.
+const maxArchiveFileSize = 1024 * 1024
+
+// archiveToolDir holds the tools of a Go toolchain, in a directory named after the
+// platform they run on.
+const archiveToolDir = "pkg/tool/"
+
+// ReadArchiveFile returns the content of the regular file name, given without the
+// top-level directory like UntarReader extracts it, from the gzipped tarball source.
+// It fails with an error wrapping os.ErrNotExist when the archive has no such file.
+func (o *osClient) ReadArchiveFile(source string, name string) ([]byte, error) {
+	var content []byte
+	found, err := scanArchive(source, func(header *tar.Header, tr *tar.Reader) (bool, error) {
+		if header.Typeflag != tar.TypeReg || stripComponent(header.Name) != name {
+			return false, nil
+		}
+		var err error
+		content, err = io.ReadAll(io.LimitReader(tr, maxArchiveFileSize))
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("archive has no %s: %w", name, os.ErrNotExist)
+	}
+	return content, nil
+}
+
+// ReadArchivePlatform returns the platform the toolchain in the gzipped tarball source
+// is built for, from its pkg/tool/<os>_<arch> directory. It fails with an error
+// wrapping os.ErrNotExist when the archive has no such directory.
+func (o *osClient) ReadArchivePlatform(source string) (domain.Platform, error) {
+	var platform domain.Platform
+	found, err := scanArchive(source, func(header *tar.Header, tr *tar.Reader) (bool, error) {
+		dir, ok := strings.CutPrefix(stripComponent(header.Name), archiveToolDir)
+		if !ok {
+			return false, nil
+		}
+		dir, _, _ = strings.Cut(dir, "/")
+		goos, goarch, ok := strings.Cut(dir, "_")
+		if !ok || goos == "" || goarch == "" {
+			return false, nil
+		}
+		platform = domain.Platform{OS: goos, Arch: goarch}
+		return true, nil
+	})
+	if err != nil {
+		return domain.Platform{}, err
+	}
+	if !found {
+		return domain.Platform{}, fmt.Errorf("archive has no %s<os>_<arch> directory: %w", archiveToolDir, os.ErrNotExist)
+	}
+	return platform, nil
+}
+
+// scanArchive calls visit with the entries of the gzipped tarball source in order,
+// until it returns true, which scanArchive then returns.
+func scanArchive(source string, visit func(header *tar.Header, tr *tar.Reader) (bool, error)) (bool, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return false, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if found, err := visit(header, tr); found || err != nil {
+			return found, err
+		}
+	}
+}
+
+// stripComponent drops the first element of an archive entry name, returning an empty
+// string for the top-level directory itself.
+func stripComponent(name string) string {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+	_, rest, _ := strings.Cut(name, "/")
+	return rest
+}
+
+// archivePath joins name to root, failing when the result escapes root.
+func archivePath(root string, name string) (string, error) {
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if !within(root, path) {
+		return "", fmt.Errorf("archive entry %q is outside of the target directory", name)
+	}
+	return path, nil
+}
+
+// checkNoSymlinks fails when any directory from root down to dir is a symlink, which
+// an earlier entry of the archive could have created to lead outside root.
+func checkNoSymlinks(root string, dir string) error {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+
+	path := root
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		path = filepath.Join(path, elem)
+		fi, err := os.Lstat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink", path)
+		}
+	}
+	return nil
+}
+
+func within(root string, path string) bool {
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
+
+// RunCommand runs name with args attached to the current standard streams, adding env
+// to the current environment. Interrupts are left to the child, so its exit code can
+// be reported back to the caller.
+func (o *osClient) RunCommand(name string, args []string, env []string) (int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+// FetchSource checks out ref of the git repository repo, a URL or a local path, into
+// target without its history. ref may be a branch, a tag or a commit. The output of git
+// is returned for the logs.
+func (o *osClient) FetchSource(ctx context.Context, repo string, ref string, target string) ([]byte, error) {
+	var output bytes.Buffer
+
+	for _, args := range [][]string{
+		{"init", "--quiet", target},
+		{"-C", target, "fetch", "--quiet", "--depth", "1", repo, ref},
+		{"-C", target, "checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		if err := cmd.Run(); err != nil {
+			return output.Bytes(), fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	return output.Bytes(), nil
+}
+
+// BuildSource runs make.bash in the Go source tree goroot, bootstrapping from the Go
+// installation at bootstrap. The output of the build is returned for the logs.
+func (o *osClient) BuildSource(ctx context.Context, goroot string, bootstrap string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "bash", "make.bash")
+	cmd.Dir = filepath.Join(goroot, "src")
+
+	// The environment of the user must not point the build at another toolchain.
+	for _, env := range os.Environ() {
+		if !strings.HasPrefix(env, "GOROOT=") && !strings.HasPrefix(env, "GOTOOLCHAIN=") {
+			cmd.Env = append(cmd.Env, env)
+		}
+	}
+	cmd.Env = append(cmd.Env, "GOROOT_BOOTSTRAP="+bootstrap, "GOTOOLCHAIN=local")
+
+	return cmd.CombinedOutput()
+}
+
+// ReadConfig decodes the TOML config file at path. A missing file yields the
+// default config.
+func (o *osClient) ReadConfig(path string) (domain.Config, error) {
+	var config domain.Config
+	if _, err := toml.DecodeFile(path, &config); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return domain.Config{}, err
+	}
+	return config, nil
+}
+
+func (o *osClient) WriteConfig(path string, config domain.Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
@@ -1,10 +1,13 @@
 package gateway
 
 import (
+	"context"
+	"io"
 	"io/fs"
 	"os"
 	"time"
 
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -17,6 +20,16 @@ func (m *OsGatewayMock) GetUserHomeDir() (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *OsGatewayMock) GetWorkingDir() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *OsGatewayMock) GetExecutable() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
 func (m *OsGatewayMock) CreateDir(path string, perm os.FileMode) error {
 	args := m.Called(path, perm)
 	return args.Error(0)
@@ -37,6 +50,11 @@ func (m *OsGatewayMock) OpenFile(path string) (*os.File, error) {
 	return args.Get(0).(*os.File), args.Error(1)
 }
 
+func (m *OsGatewayMock) AppendFile(path string) (*os.File, error) {
+	args := m.Called(path)
+	return args.Get(0).(*os.File), args.Error(1)
+}
+
 func (m *OsGatewayMock) Stat(path string) (os.FileInfo, error) {
 	args := m.Called(path)
 	return args.Get(0).(os.FileInfo), args.Error(1)
@@ -57,19 +75,64 @@ func (m *OsGatewayMock) RemoveFile(path string) error {
 	return args.Error(0)
 }
 
+func (m *OsGatewayMock) ReadDir(path string) ([]os.DirEntry, error) {
+	args := m.Called(path)
+	return args.Get(0).([]os.DirEntry), args.Error(1)
+}
+
+func (m *OsGatewayMock) Rename(source string, target string) error {
+	args := m.Called(source, target)
+	return args.Error(0)
+}
+
+func (m *OsGatewayMock) CreateSymlink(target string, link string) error {
+	args := m.Called(target, link)
+	return args.Error(0)
+}
+
+func (m *OsGatewayMock) ReadSymlink(link string) (string, error) {
+	args := m.Called(link)
+	return args.String(0), args.Error(1)
+}
+
 func (m *OsGatewayMock) GetEnv(key string) string {
 	args := m.Called(key)
 	return args.String(0)
 }
 
-func (m *OsGatewayMock) Untar(source string, target string) error {
-	args := m.Called(source, target)
+func (m *OsGatewayMock) Untar(ctx context.Context, source string, target string, progress func(written int64, total int64)) error {
+	args := m.Called(ctx, source, target, progress)
 	return args.Error(0)
 }
 
-func (m *OsGatewayMock) GetInstalledGoVersion() (string, error) {
-	args := m.Called()
-	return args.String(0), args.Error(1)
+func (m *OsGatewayMock) UntarReader(ctx context.Context, reader io.Reader, target string) error {
+	args := m.Called(ctx, reader, target)
+	return args.Error(0)
+}
+
+func (m *OsGatewayMock) ReadArchiveFile(source string, name string) ([]byte, error) {
+	args := m.Called(source, name)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *OsGatewayMock) ReadArchivePlatform(source string) (domain.Platform, error) {
+	args := m.Called(source)
+	return args.Get(0).(domain.Platform), args.Error(1)
+}
+
+func (m *OsGatewayMock) RunCommand(name string, args []string, env []string) (int, error) {
+	a := m.Called(name, args, env)
+	return a.Int(0), a.Error(1)
+}
+
+func (m *OsGatewayMock) FetchSource(ctx context.Context, repo string, ref string, target string) ([]byte, error) {
+	args := m.Called(ctx, repo, ref, target)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *OsGatewayMock) BuildSource(ctx context.Context, goroot string, bootstrap string) ([]byte, error) {
+	args := m.Called(ctx, goroot, bootstrap)
+	return args.Get(0).([]byte), args.Error(1)
 }
 
 type FileInfoMock struct {
@@ -105,3 +168,13 @@ func (f *FileInfoMock) Sys() any {
 	args := f.Called()
 	return args.Get(0)
 }
+
+func (m *OsGatewayMock) ReadConfig(path string) (domain.Config, error) {
+	args := m.Called(path)
+	return args.Get(0).(domain.Config), args.Error(1)
+}
+
+func (m *OsGatewayMock) WriteConfig(path string, config domain.Config) error {
+	args := m.Called(path, config)
+	return args.Error(0)
+}
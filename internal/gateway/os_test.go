@@ -1,9 +1,17 @@
 package gateway_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
 	"github.com/stretchr/testify/suite"
 )
@@ -27,6 +35,18 @@ func (r *osGatewaySuite) TestGetUserHomeDir() {
 	r.NotEmpty(usrHomeDir)
 }
 
+func (r *osGatewaySuite) TestGetWorkingDir() {
+	wd, err := r.gateway.GetWorkingDir()
+	r.NoError(err)
+	r.NotEmpty(wd)
+}
+
+func (r *osGatewaySuite) TestGetExecutable() {
+	executable, err := r.gateway.GetExecutable()
+	r.NoError(err)
+	r.True(filepath.IsAbs(executable))
+}
+
 func (r *osGatewaySuite) TestStat() {
 	fi, err := r.gateway.Stat(".")
 	r.NoError(err)
@@ -53,6 +73,20 @@ func (r *osGatewaySuite) TestOpenFile() {
 	r.NotNil(file)
 }
 
+func (r *osGatewaySuite) TestAppendFile() {
+	path := filepath.Join(r.T().TempDir(), "go1.22.3.tar.gz.part")
+	r.NoError(os.WriteFile(path, []byte("part"), 0644))
+
+	file, err := r.gateway.AppendFile(path)
+	r.NoError(err)
+	_, err = file.WriteString("ial")
+	r.NoError(err)
+	r.NoError(file.Close())
+
+	content, _ := os.ReadFile(path)
+	r.Equal("partial", string(content))
+}
+
 func (r *osGatewaySuite) TestReadFile() {
 	data, err := r.gateway.ReadFile("os_test.go")
 	r.NoError(err)
@@ -66,8 +100,468 @@ func (r *osGatewaySuite) TestWriteFile() {
 	r.NoError(err)
 }
 
+func (r *osGatewaySuite) TestRename() {
+	dir := r.T().TempDir()
+	source := filepath.Join(dir, "go1.22.3.tar.gz.part")
+	target := filepath.Join(dir, "go1.22.3.tar.gz")
+	r.NoError(os.WriteFile(source, []byte("archive"), 0644))
+
+	err := r.gateway.Rename(source, target)
+	r.NoError(err)
+
+	r.NoFileExists(source)
+	r.FileExists(target)
+}
+
+func (r *osGatewaySuite) TestReadDir() {
+	entries, err := r.gateway.ReadDir(".")
+	r.NoError(err)
+	r.NotEmpty(entries)
+}
+
+func (r *osGatewaySuite) TestCreateAndReadSymlink() {
+	dir := r.T().TempDir()
+	link := filepath.Join(dir, "current")
+
+	err := r.gateway.CreateSymlink(filepath.Join(dir, "go1.21.0"), link)
+	r.NoError(err)
+	err = r.gateway.CreateSymlink(filepath.Join(dir, "go1.22.0"), link)
+	r.NoError(err)
+
+	target, err := r.gateway.ReadSymlink(link)
+	r.NoError(err)
+	r.Equal(filepath.Join(dir, "go1.22.0"), target)
+}
+
 func (r *osGatewaySuite) TestGetEnv() {
 	os.Setenv("XPTO", "test")
 	env := r.gateway.GetEnv("XPTO")
 	r.Equal("test", env)
 }
+
+func (r *osGatewaySuite) TestRunCommand() {
+	code, err := r.gateway.RunCommand("sh", []string{"-c", "test \"$XPTO\" = test"}, []string{"XPTO=test"})
+	r.NoError(err)
+	r.Equal(0, code)
+
+	code, err = r.gateway.RunCommand("sh", []string{"-c", "exit 3"}, nil)
+	r.NoError(err)
+	r.Equal(3, code)
+
+	code, err = r.gateway.RunCommand("xpto-command-not-found", nil, nil)
+	r.Error(err)
+	r.Equal(-1, code)
+}
+
+func (r *osGatewaySuite) TestFetchSource() {
+	repo := r.gitRepo()
+	first := r.git(repo, "rev-parse", "HEAD~1")
+
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{ref: domain.TipRef, expected: "go1.24"},
+		{ref: "master", expected: "go1.24"},
+		{ref: "go1.23", expected: "go1.23"},
+		{ref: first, expected: "go1.23"},
+	}
+
+	for _, tc := range tests {
+		r.Run(tc.ref, func() {
+			target := filepath.Join(r.T().TempDir(), "gotip")
+
+			_, err := r.gateway.FetchSource(context.Background(), repo, tc.ref, target)
+			r.NoError(err)
+
+			version, _ := os.ReadFile(filepath.Join(target, "VERSION"))
+			r.Equal(tc.expected, string(version))
+		})
+	}
+}
+
+func (r *osGatewaySuite) TestFetchSourceUnknownRef() {
+	target := filepath.Join(r.T().TempDir(), "gotip")
+
+	output, err := r.gateway.FetchSource(context.Background(), r.gitRepo(), "xpto", target)
+
+	r.ErrorContains(err, "git -C "+target+" fetch")
+	r.Contains(string(output), "xpto")
+}
+
+func (r *osGatewaySuite) TestBuildSource() {
+	goroot := r.T().TempDir()
+	r.NoError(os.Mkdir(filepath.Join(goroot, "src"), 0755))
+	r.NoError(os.WriteFile(filepath.Join(goroot, "src", "make.bash"), []byte("echo \"$GOROOT_BOOTSTRAP $GOTOOLCHAIN $GOROOT\"\nexit $EXIT_CODE\n"), 0755))
+	r.T().Setenv("GOROOT", "/usr/local/go")
+
+	r.T().Setenv("EXIT_CODE", "0")
+	output, err := r.gateway.BuildSource(context.Background(), goroot, "/home/user/.govm/versions/go1.22.3")
+	r.NoError(err)
+	r.Equal("/home/user/.govm/versions/go1.22.3 local \n", string(output))
+
+	r.T().Setenv("EXIT_CODE", "2")
+	_, err = r.gateway.BuildSource(context.Background(), goroot, "/home/user/.govm/versions/go1.22.3")
+	r.Error(err)
+}
+
+func (r *osGatewaySuite) TestUntar() {
+	dir := r.T().TempDir()
+	source := filepath.Join(dir, "go1.22.3.linux-amd64.tar.gz")
+	target := filepath.Join(dir, "go1.22.3")
+	r.NoError(os.WriteFile(source, r.tarball([]tar.Header{
+		{Typeflag: tar.TypeDir, Name: "go/", Mode: 0755},
+		{Typeflag: tar.TypeDir, Name: "go/bin/", Mode: 0755},
+		{Typeflag: tar.TypeReg, Name: "go/bin/go", Mode: 0755, Size: 2},
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+		{Typeflag: tar.TypeSymlink, Name: "go/bin/go1.22", Linkname: "go"},
+		{Typeflag: tar.TypeLink, Name: "go/bin/gofmt", Linkname: "go/bin/go"},
+	}, "go", "go1.22.3"), 0644))
+
+	err := r.gateway.Untar(context.Background(), source, target, nil)
+	r.NoError(err)
+
+	content, _ := os.ReadFile(filepath.Join(target, "VERSION"))
+	r.Equal("go1.22.3", string(content))
+
+	fi, err := os.Stat(filepath.Join(target, "bin", "go"))
+	r.NoError(err)
+	r.Equal(os.FileMode(0755), fi.Mode().Perm())
+
+	fi, err = os.Stat(filepath.Join(target, "VERSION"))
+	r.NoError(err)
+	r.Equal(os.FileMode(0644), fi.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(target, "bin", "go1.22"))
+	r.NoError(err)
+	r.Equal("go", link)
+
+	content, _ = os.ReadFile(filepath.Join(target, "bin", "gofmt"))
+	r.Equal("go", string(content))
+}
+
+func (r *osGatewaySuite) TestUntarNotExists() {
+	dir := r.T().TempDir()
+	err := r.gateway.Untar(context.Background(), filepath.Join(dir, "go1.22.3.linux-amd64.tar.gz"), dir, nil)
+	r.ErrorIs(err, os.ErrNotExist)
+}
+
+func (r *osGatewaySuite) TestUntarProgress() {
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/bin/go", Mode: 0755, Size: 2},
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+	}, "go", "go1.22.3")
+	source := filepath.Join(r.T().TempDir(), "go1.22.3.linux-amd64.tar.gz")
+	r.NoError(os.WriteFile(source, archive, 0644))
+
+	var written, total int64
+	err := r.gateway.Untar(context.Background(), source, r.T().TempDir(), func(w int64, t int64) {
+		written, total = w, t
+	})
+
+	r.NoError(err)
+	r.Equal(int64(len(archive)), written)
+	r.Equal(int64(len(archive)), total)
+}
+
+func (r *osGatewaySuite) TestUntarReader() {
+	target := filepath.Join(r.T().TempDir(), "go1.22.3")
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/bin/go", Mode: 0755, Size: 2},
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+	}, "go", "go1.22.3")
+
+	err := r.gateway.UntarReader(context.Background(), bytes.NewReader(archive), target)
+	r.NoError(err)
+
+	content, _ := os.ReadFile(filepath.Join(target, "VERSION"))
+	r.Equal("go1.22.3", string(content))
+
+	fi, err := os.Stat(filepath.Join(target, "bin", "go"))
+	r.NoError(err)
+	r.Equal(os.FileMode(0755), fi.Mode().Perm())
+}
+
+func (r *osGatewaySuite) TestUntarPathTraversal() {
+	dir := r.T().TempDir()
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/../../evil", Mode: 0644, Size: 4},
+	}, "evil")
+
+	err := r.untar(context.Background(), archive, filepath.Join(dir, "go1.22.3"))
+
+	r.ErrorContains(err, "outside of the target directory")
+	r.NoFileExists(filepath.Join(dir, "evil"))
+}
+
+func (r *osGatewaySuite) TestUntarSymlinkOutsideTarget() {
+	for _, linkname := range []string{"../../etc/passwd", "/etc/passwd"} {
+		target := r.T().TempDir()
+		archive := r.tarball([]tar.Header{
+			{Typeflag: tar.TypeSymlink, Name: "go/passwd", Linkname: linkname},
+		})
+
+		err := r.untar(context.Background(), archive, target)
+
+		r.Error(err)
+		r.NoFileExists(filepath.Join(target, "passwd"))
+	}
+}
+
+func (r *osGatewaySuite) TestUntarThroughSymlinkChain() {
+	dir := r.T().TempDir()
+	target := filepath.Join(dir, "staging")
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeSymlink, Name: "go/d", Linkname: "."},
+		{Typeflag: tar.TypeSymlink, Name: "go/d/e", Linkname: ".."},
+		{Typeflag: tar.TypeReg, Name: "go/e/pwned", Mode: 0644, Size: 5},
+	}, "pwned")
+
+	err := r.untar(context.Background(), archive, target)
+
+	r.ErrorContains(err, "is a symlink")
+	r.NoFileExists(filepath.Join(dir, "pwned"))
+	r.NoFileExists(filepath.Join(target, "e"))
+}
+
+func (r *osGatewaySuite) TestUntarHardLinkThroughSymlink() {
+	dir := r.T().TempDir()
+	target := filepath.Join(dir, "staging")
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+		{Typeflag: tar.TypeSymlink, Name: "go/d", Linkname: "."},
+		{Typeflag: tar.TypeLink, Name: "go/h", Linkname: "go/d/VERSION"},
+	}, "go1.22.3")
+
+	err := r.untar(context.Background(), archive, target)
+
+	r.ErrorContains(err, "is a symlink")
+	r.NoFileExists(filepath.Join(target, "h"))
+}
+
+func (r *osGatewaySuite) TestUntarHardLinkToSymlink() {
+	target := r.T().TempDir()
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeSymlink, Name: "go/d", Linkname: "."},
+		{Typeflag: tar.TypeLink, Name: "go/h", Linkname: "go/d"},
+	})
+
+	err := r.untar(context.Background(), archive, target)
+
+	r.ErrorContains(err, "not a regular file")
+}
+
+func (r *osGatewaySuite) TestUntarReplacesSymlink() {
+	dir := r.T().TempDir()
+	outside := filepath.Join(dir, "outside")
+	r.NoError(os.WriteFile(outside, []byte("keep"), 0644))
+	target := filepath.Join(dir, "staging")
+	r.NoError(os.Mkdir(target, 0755))
+	r.NoError(os.Symlink(outside, filepath.Join(target, "VERSION")))
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+	}, "go1.22.3")
+
+	err := r.untar(context.Background(), archive, target)
+
+	r.NoError(err)
+	content, _ := os.ReadFile(outside)
+	r.Equal("keep", string(content))
+	content, _ = os.ReadFile(filepath.Join(target, "VERSION"))
+	r.Equal("go1.22.3", string(content))
+}
+
+func (r *osGatewaySuite) TestUntarDirectoryOverSymlink() {
+	dir := r.T().TempDir()
+	target := filepath.Join(dir, "staging")
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeSymlink, Name: "go/d", Linkname: "."},
+		{Typeflag: tar.TypeDir, Name: "go/d/", Mode: 0777},
+	})
+
+	err := r.untar(context.Background(), archive, target)
+
+	r.ErrorContains(err, "is a symlink")
+}
+
+func (r *osGatewaySuite) TestUntarCleansSymlinkTarget() {
+	target := r.T().TempDir()
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeSymlink, Name: "go/s", Linkname: "."},
+		{Typeflag: tar.TypeSymlink, Name: "go/t", Linkname: "s/.."},
+	})
+
+	err := r.untar(context.Background(), archive, target)
+
+	r.NoError(err)
+	link, err := os.Readlink(filepath.Join(target, "t"))
+	r.NoError(err)
+	r.Equal(".", link)
+}
+
+func (r *osGatewaySuite) TestUntarUnsupportedType() {
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeFifo, Name: "go/fifo", Mode: 0644},
+	})
+
+	err := r.untar(context.Background(), archive, r.T().TempDir())
+
+	r.ErrorContains(err, "unsupported type")
+}
+
+func (r *osGatewaySuite) TestUntarInvalidArchive() {
+	err := r.untar(context.Background(), []byte("xpto"), r.T().TempDir())
+	r.Error(err)
+}
+
+func (r *osGatewaySuite) TestUntarCancelled() {
+	target := r.T().TempDir()
+	archive := r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+	}, "go1.22.3")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.untar(ctx, archive, target)
+
+	r.ErrorIs(err, context.Canceled)
+	r.NoFileExists(filepath.Join(target, "VERSION"))
+}
+
+func (r *osGatewaySuite) TestReadArchiveFile() {
+	source := filepath.Join(r.T().TempDir(), "go.tar.gz")
+	r.NoError(os.WriteFile(source, r.tarball([]tar.Header{
+		{Typeflag: tar.TypeDir, Name: "go/", Mode: 0755},
+		{Typeflag: tar.TypeReg, Name: "go/bin/VERSION", Mode: 0644, Size: 5},
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+	}, "other", "go1.22.3"), 0644))
+
+	content, err := r.gateway.ReadArchiveFile(source, "VERSION")
+
+	r.NoError(err)
+	r.Equal("go1.22.3", string(content))
+}
+
+func (r *osGatewaySuite) TestReadArchiveFileNotFound() {
+	source := filepath.Join(r.T().TempDir(), "go.tar.gz")
+	r.NoError(os.WriteFile(source, r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/README.md", Mode: 0644, Size: 6},
+	}, "readme"), 0644))
+
+	_, err := r.gateway.ReadArchiveFile(source, "VERSION")
+
+	r.ErrorIs(err, os.ErrNotExist)
+}
+
+func (r *osGatewaySuite) TestReadArchivePlatform() {
+	source := filepath.Join(r.T().TempDir(), "go.tar.gz")
+	r.NoError(os.WriteFile(source, r.tarball([]tar.Header{
+		{Typeflag: tar.TypeDir, Name: "go/", Mode: 0755},
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+		{Typeflag: tar.TypeDir, Name: "go/pkg/tool/", Mode: 0755},
+		{Typeflag: tar.TypeReg, Name: "go/pkg/tool/darwin_arm64/compile", Mode: 0755, Size: 7},
+	}, "go1.22.3", "compile"), 0644))
+
+	platform, err := r.gateway.ReadArchivePlatform(source)
+
+	r.NoError(err)
+	r.Equal(domain.Platform{OS: "darwin", Arch: "arm64"}, platform)
+}
+
+func (r *osGatewaySuite) TestReadArchivePlatformNotFound() {
+	source := filepath.Join(r.T().TempDir(), "go.tar.gz")
+	r.NoError(os.WriteFile(source, r.tarball([]tar.Header{
+		{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0644, Size: 8},
+	}, "go1.22.3"), 0644))
+
+	_, err := r.gateway.ReadArchivePlatform(source)
+
+	r.ErrorIs(err, os.ErrNotExist)
+}
+
+// untar extracts archive into target through a file, as Untar reads it.
+func (r *osGatewaySuite) untar(ctx context.Context, archive []byte, target string) error {
+	source := filepath.Join(r.T().TempDir(), "go.tar.gz")
+	r.NoError(os.WriteFile(source, archive, 0644))
+	return r.gateway.Untar(ctx, source, target, nil)
+}
+
+// tarball builds a gzipped tarball from headers, taking the content of each regular
+// file from contents in order.
+func (r *osGatewaySuite) tarball(headers []tar.Header, contents ...string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, header := range headers {
+		r.NoError(tw.WriteHeader(&header))
+		if header.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(contents[0]))
+			r.NoError(err)
+			contents = contents[1:]
+		}
+	}
+	r.NoError(tw.Close())
+	r.NoError(gz.Close())
+	return buf.Bytes()
+}
+
+func (r *osGatewaySuite) TestReadConfig() {
+	path := filepath.Join(r.T().TempDir(), "config.toml")
+	r.NoError(os.WriteFile(path, []byte("mirror = \"https://golang.google.cn/dl\"\nindex_url = \"https://golang.google.cn/dl/?mode=json\"\n"), 0644))
+
+	config, err := r.gateway.ReadConfig(path)
+
+	r.NoError(err)
+	r.Equal(domain.Config{Mirror: "https://golang.google.cn/dl", IndexURL: "https://golang.google.cn/dl/?mode=json"}, config)
+}
+
+func (r *osGatewaySuite) TestReadConfigNotExists() {
+	config, err := r.gateway.ReadConfig(filepath.Join(r.T().TempDir(), "config.toml"))
+
+	r.NoError(err)
+	r.Equal(domain.Config{}, config)
+}
+
+func (r *osGatewaySuite) TestReadConfigInvalid() {
+	path := filepath.Join(r.T().TempDir(), "config.toml")
+	r.NoError(os.WriteFile(path, []byte("mirror = "), 0644))
+
+	_, err := r.gateway.ReadConfig(path)
+
+	r.Error(err)
+}
+
+func (r *osGatewaySuite) TestWriteConfig() {
+	path := filepath.Join(r.T().TempDir(), ".govm", "config.toml")
+	config := domain.Config{Mirror: "https://golang.google.cn/dl", Cache: domain.OffCachePolicy}
+
+	err := r.gateway.WriteConfig(path, config)
+	r.NoError(err)
+
+	content, _ := os.ReadFile(path)
+	r.Equal("mirror = \"https://golang.google.cn/dl\"\ncache = \"off\"\n", string(content))
+
+	read, err := r.gateway.ReadConfig(path)
+	r.NoError(err)
+	r.Equal(config, read)
+}
+
+// gitRepo creates a repository with two commits on master, writing VERSION as
+// "go1.23" and then "go1.24", the first one tagged go1.23.
+func (r *osGatewaySuite) gitRepo() string {
+	dir := r.T().TempDir()
+	r.git(dir, "init", "--quiet", "--initial-branch", "master")
+	for _, version := range []string{"go1.23", "go1.24"} {
+		r.NoError(os.WriteFile(filepath.Join(dir, "VERSION"), []byte(version), 0644))
+		r.git(dir, "add", "VERSION")
+		r.git(dir, "-c", "user.name=govm", "-c", "user.email=govm@example.com", "commit", "--quiet", "-m", version)
+	}
+	r.git(dir, "tag", "go1.23", "HEAD~1")
+	return dir
+}
+
+func (r *osGatewaySuite) git(dir string, args ...string) string {
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	r.Require().NoError(err, string(output))
+	return strings.TrimSpace(string(output))
+}
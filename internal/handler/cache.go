@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/service"
+)
+
+type CacheHandler interface {
+	List(ctx context.Context) error
+	Clean(ctx context.Context) error
+	Prune(ctx context.Context, keep int) (int, error)
+}
+
+type cacheHandler struct {
+	sharedSvc service.SharedService
+}
+
+func NewCache(sharedSvc service.SharedService) CacheHandler {
+	return &cacheHandler{
+		sharedSvc: sharedSvc,
+	}
+}
+
+func (r *cacheHandler) List(ctx context.Context) error {
+	slog.InfoContext(ctx, "Listing cached archives", slog.String("CacheHandler", "List"))
+
+	action := &domain.Action{}
+	if err := r.sharedSvc.CheckUserHome(ctx, action); err != nil {
+		return err
+	}
+
+	archives, err := r.sharedSvc.GetCachedArchives(ctx, action)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, a := range archives {
+		total += a.Size
+	}
+
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("Cached Go archives in %s \n", action.CacheDir())
+	fmt.Println(strings.Repeat("=", 100))
+	for _, a := range archives {
+		fmt.Println(a.String())
+	}
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("%d archive(s), %s\n", len(archives), domain.FormatSize(total))
+	fmt.Println(strings.Repeat("=", 100))
+
+	return nil
+}
+
+func (r *cacheHandler) Clean(ctx context.Context) error {
+	slog.InfoContext(ctx, "Cleaning cache", slog.String("CacheHandler", "Clean"))
+
+	action := &domain.Action{}
+	if err := r.sharedSvc.CheckUserHome(ctx, action); err != nil {
+		return err
+	}
+
+	return r.sharedSvc.CleanCache(ctx, action)
+}
+
+// Prune removes all but the keep most recently downloaded archives and
+// returns how many were removed.
+func (r *cacheHandler) Prune(ctx context.Context, keep int) (int, error) {
+	slog.InfoContext(ctx, "Pruning cache", slog.String("CacheHandler", "Prune"), slog.Int("keep", keep))
+
+	if keep < 0 {
+		return 0, domain.NewInvalidCacheKeepError(keep)
+	}
+
+	action := &domain.Action{}
+	if err := r.sharedSvc.CheckUserHome(ctx, action); err != nil {
+		return 0, err
+	}
+
+	archives, err := r.sharedSvc.GetCachedArchives(ctx, action)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(archives) <= keep {
+		return 0, nil
+	}
+
+	removed := 0
+	for _, a := range archives[keep:] {
+		if err := r.sharedSvc.RemoveCachedArchive(ctx, action, a); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CacheHandlerMock struct {
+	mock.Mock
+}
+
+func (m *CacheHandlerMock) List(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *CacheHandlerMock) Clean(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *CacheHandlerMock) Prune(ctx context.Context, keep int) (int, error) {
+	args := m.Called(ctx, keep)
+	return args.Int(0), args.Error(1)
+}
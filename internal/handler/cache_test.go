@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/stretchr/testify/suite"
+)
+
+type cacheHandlerSuite struct {
+	suite.Suite
+	ctx       context.Context
+	archives  []domain.CachedArchive
+	sharedSvc *service.SharedServiceMock
+	handler   handler.CacheHandler
+}
+
+func TestCacheHandler(t *testing.T) {
+	suite.Run(t, new(cacheHandlerSuite))
+}
+
+func (r *cacheHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.archives = []domain.CachedArchive{
+		{Checksum: "ccc", Filename: "go1.23.0.linux-amd64.tar.gz", Size: 2048, ModTime: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC)},
+		{Checksum: "bbb", Filename: "go1.22.3.linux-amd64.tar.gz", Size: 1024, ModTime: time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)},
+		{Checksum: "aaa", Filename: "go1.21.0.linux-amd64.tar.gz", Size: 512, ModTime: time.Date(2023, 8, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	r.sharedSvc = new(service.SharedServiceMock)
+	r.handler = handler.NewCache(r.sharedSvc)
+}
+
+func (r *cacheHandlerSuite) TearDownTest() {
+	r.sharedSvc.AssertExpectations(r.T())
+}
+
+func (r *cacheHandlerSuite) TestListSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetCachedArchives", r.ctx, &domain.Action{}).Return(r.archives, nil)
+
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.handler.List(r.ctx)
+	})
+
+	// Assert
+	expected := strings.Join(
+		[]string{
+			strings.Repeat("=", 100) + "\n",
+			"Cached Go archives in .govm/cache \n",
+			strings.Repeat("=", 100) + "\n",
+			r.archives[0].String() + "\n",
+			r.archives[1].String() + "\n",
+			r.archives[2].String() + "\n",
+			strings.Repeat("=", 100) + "\n",
+			"3 archive(s), 3.5 KiB\n",
+			strings.Repeat("=", 100) + "\n",
+		},
+		"",
+	)
+
+	r.NoError(err)
+	r.Equal(expected, output)
+}
+
+func (r *cacheHandlerSuite) TestListCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.List(r.ctx)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *cacheHandlerSuite) TestListGetCachedArchivesError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetCachedArchives", r.ctx, &domain.Action{}).Return([]domain.CachedArchive{}, errors.New("error"))
+
+	// Act
+	err := r.handler.List(r.ctx)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *cacheHandlerSuite) TestCleanSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("CleanCache", r.ctx, &domain.Action{}).Return(nil)
+
+	// Act
+	err := r.handler.Clean(r.ctx)
+
+	// Assert
+	r.NoError(err)
+}
+
+func (r *cacheHandlerSuite) TestCleanCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Clean(r.ctx)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *cacheHandlerSuite) TestPruneSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetCachedArchives", r.ctx, &domain.Action{}).Return(r.archives, nil)
+	r.sharedSvc.On("RemoveCachedArchive", r.ctx, &domain.Action{}, r.archives[1]).Return(nil)
+	r.sharedSvc.On("RemoveCachedArchive", r.ctx, &domain.Action{}, r.archives[2]).Return(nil)
+
+	// Act
+	removed, err := r.handler.Prune(r.ctx, 1)
+
+	// Assert
+	r.NoError(err)
+	r.Equal(2, removed)
+}
+
+func (r *cacheHandlerSuite) TestPruneNothingToRemove() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetCachedArchives", r.ctx, &domain.Action{}).Return(r.archives, nil)
+
+	// Act
+	removed, err := r.handler.Prune(r.ctx, 3)
+
+	// Assert
+	r.NoError(err)
+	r.Equal(0, removed)
+}
+
+func (r *cacheHandlerSuite) TestPruneInvalidKeep() {
+	// Act
+	removed, err := r.handler.Prune(r.ctx, -1)
+
+	// Assert
+	r.Error(err)
+	r.Equal(domain.NewInvalidCacheKeepError(-1), err)
+	r.Equal(0, removed)
+}
+
+func (r *cacheHandlerSuite) TestPruneGetCachedArchivesError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetCachedArchives", r.ctx, &domain.Action{}).Return([]domain.CachedArchive{}, errors.New("error"))
+
+	// Act
+	removed, err := r.handler.Prune(r.ctx, 1)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(0, removed)
+}
+
+func (r *cacheHandlerSuite) TestPruneRemoveCachedArchiveError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetCachedArchives", r.ctx, &domain.Action{}).Return(r.archives, nil)
+	r.sharedSvc.On("RemoveCachedArchive", r.ctx, &domain.Action{}, r.archives[1]).Return(nil)
+	r.sharedSvc.On("RemoveCachedArchive", r.ctx, &domain.Action{}, r.archives[2]).Return(errors.New("error"))
+
+	// Act
+	removed, err := r.handler.Prune(r.ctx, 1)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(1, removed)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/service"
+)
+
+type ConfigHandler interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string) error
+	List(ctx context.Context) error
+	Path(ctx context.Context) (string, error)
+}
+
+type configHandler struct {
+	sharedSvc service.SharedService
+	config    *domain.Config
+}
+
+func NewConfig(sharedSvc service.SharedService, config *domain.Config) ConfigHandler {
+	return &configHandler{
+		sharedSvc: sharedSvc,
+		config:    config,
+	}
+}
+
+// Get returns the value in effect for key, including environment overrides.
+func (r *configHandler) Get(ctx context.Context, key string) (string, error) {
+	slog.InfoContext(ctx, "Getting config", slog.String("ConfigHandler", "Get"), slog.String("key", key))
+
+	return r.config.Get(key)
+}
+
+// Set stores value for key in the config file, leaving the other keys untouched.
+func (r *configHandler) Set(ctx context.Context, key string, value string) error {
+	slog.InfoContext(ctx, "Setting config", slog.String("ConfigHandler", "Set"), slog.String("key", key), slog.String("value", value))
+
+	action := &domain.Action{}
+	if err := r.sharedSvc.CheckUserHome(ctx, action); err != nil {
+		return err
+	}
+
+	config, err := r.sharedSvc.ReadConfig(ctx, action)
+	if err != nil {
+		return err
+	}
+
+	if err := config.Set(key, value); err != nil {
+		return err
+	}
+
+	return r.sharedSvc.WriteConfig(ctx, action, config)
+}
+
+func (r *configHandler) List(ctx context.Context) error {
+	slog.InfoContext(ctx, "Listing config", slog.String("ConfigHandler", "List"))
+
+	fmt.Println(r.config.String())
+
+	return nil
+}
+
+func (r *configHandler) Path(ctx context.Context) (string, error) {
+	slog.InfoContext(ctx, "Getting config path", slog.String("ConfigHandler", "Path"))
+
+	action := &domain.Action{}
+	if err := r.sharedSvc.CheckUserHome(ctx, action); err != nil {
+		return "", err
+	}
+
+	return action.ConfigFile(), nil
+}
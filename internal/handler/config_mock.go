@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ConfigHandlerMock struct {
+	mock.Mock
+}
+
+func (m *ConfigHandlerMock) Get(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *ConfigHandlerMock) Set(ctx context.Context, key string, value string) error {
+	return m.Called(ctx, key, value).Error(0)
+}
+
+func (m *ConfigHandlerMock) List(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *ConfigHandlerMock) Path(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,158 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type configHandlerSuite struct {
+	suite.Suite
+	ctx       context.Context
+	config    *domain.Config
+	sharedSvc *service.SharedServiceMock
+	handler   handler.ConfigHandler
+}
+
+func TestConfigHandler(t *testing.T) {
+	suite.Run(t, new(configHandlerSuite))
+}
+
+func (r *configHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.config = &domain.Config{Mirror: "https://golang.google.cn/dl"}
+	r.sharedSvc = new(service.SharedServiceMock)
+	r.handler = handler.NewConfig(r.sharedSvc, r.config)
+}
+
+func (r *configHandlerSuite) TearDownTest() {
+	r.sharedSvc.AssertExpectations(r.T())
+}
+
+func (r *configHandlerSuite) TestGetSuccess() {
+	// Act
+	value, err := r.handler.Get(r.ctx, "mirror")
+
+	// Assert
+	r.NoError(err)
+	r.Equal("https://golang.google.cn/dl", value)
+}
+
+func (r *configHandlerSuite) TestGetInvalidKey() {
+	// Act
+	_, err := r.handler.Get(r.ctx, "proxy")
+
+	// Assert
+	r.Error(err)
+	r.Equal(domain.NewInvalidConfigKeyError("proxy"), err)
+}
+
+func (r *configHandlerSuite) TestSetSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("ReadConfig", r.ctx, &domain.Action{}).Return(domain.Config{Cache: domain.OffCachePolicy}, nil)
+	r.sharedSvc.On("WriteConfig", r.ctx, &domain.Action{}, domain.Config{Cache: domain.OffCachePolicy, Color: domain.NeverColor}).Return(nil)
+
+	// Act
+	err := r.handler.Set(r.ctx, "color", "never")
+
+	// Assert
+	r.NoError(err)
+}
+
+func (r *configHandlerSuite) TestSetCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Set(r.ctx, "color", "never")
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *configHandlerSuite) TestSetReadConfigError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("ReadConfig", r.ctx, &domain.Action{}).Return(domain.Config{}, errors.New("error"))
+
+	// Act
+	err := r.handler.Set(r.ctx, "color", "never")
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *configHandlerSuite) TestSetInvalidValue() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("ReadConfig", r.ctx, &domain.Action{}).Return(domain.Config{}, nil)
+
+	// Act
+	err := r.handler.Set(r.ctx, "color", "rainbow")
+
+	// Assert
+	r.Error(err)
+	r.Equal(domain.NewInvalidConfigValueError("color", "rainbow"), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "WriteConfig", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (r *configHandlerSuite) TestSetWriteConfigError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("ReadConfig", r.ctx, &domain.Action{}).Return(domain.Config{}, nil)
+	r.sharedSvc.On("WriteConfig", r.ctx, &domain.Action{}, domain.Config{Color: domain.NeverColor}).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Set(r.ctx, "color", "never")
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *configHandlerSuite) TestListSuccess() {
+	// Act
+	output, err := test.CaptureOutput(func() error {
+		return r.handler.List(r.ctx)
+	})
+
+	// Assert
+	r.NoError(err)
+	r.Equal(r.config.String()+"\n", output)
+}
+
+func (r *configHandlerSuite) TestPathSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Action).HomeDir = "/home/fake" }).
+		Return(nil)
+
+	// Act
+	path, err := r.handler.Path(r.ctx)
+
+	// Assert
+	r.NoError(err)
+	r.Equal("/home/fake/.govm/config.toml", path)
+}
+
+func (r *configHandlerSuite) TestPathCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(errors.New("error"))
+
+	// Act
+	_, err := r.handler.Path(r.ctx)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/service"
+)
+
+type ExecHandler interface {
+	Handle(ctx context.Context, exec *domain.Action, name string, args []string) (int, error)
+}
+
+type execHandler struct {
+	sharedSvc service.SharedService
+}
+
+func NewExec(sharedSvc service.SharedService) ExecHandler {
+	return &execHandler{
+		sharedSvc: sharedSvc,
+	}
+}
+
+func (r *execHandler) Handle(ctx context.Context, exec *domain.Action, name string, args []string) (int, error) {
+	slog.InfoContext(ctx, "Running command with Go version", slog.String("ExecHandler", "Handle"), slog.String("version", exec.Version), slog.String("name", name))
+
+	steps := []func() error{
+		func() error { return r.sharedSvc.CheckUserHome(ctx, exec) },
+		func() error { return r.sharedSvc.CheckLocalVersion(ctx, exec) },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return 1, err
+		}
+	}
+
+	return r.sharedSvc.RunWithVersion(ctx, exec, name, args)
+}
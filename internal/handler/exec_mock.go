@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type ExecHandlerMock struct {
+	mock.Mock
+}
+
+func (m *ExecHandlerMock) Handle(ctx context.Context, exec *domain.Action, name string, args []string) (int, error) {
+	a := m.Called(ctx, exec, name, args)
+	return a.Int(0), a.Error(1)
+}
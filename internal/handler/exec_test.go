@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/stretchr/testify/suite"
+)
+
+type execHandlerSuite struct {
+	suite.Suite
+	ctx       context.Context
+	action    *domain.Action
+	args      []string
+	sharedSvc *service.SharedServiceMock
+	handler   handler.ExecHandler
+}
+
+func TestExecHandler(t *testing.T) {
+	suite.Run(t, new(execHandlerSuite))
+}
+
+func (r *execHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.action = &domain.Action{Version: "go1.22.3"}
+	r.args = []string{"test", "./..."}
+	r.sharedSvc = new(service.SharedServiceMock)
+	r.handler = handler.NewExec(r.sharedSvc)
+}
+
+func (r *execHandlerSuite) TearDownTest() {
+	r.sharedSvc.AssertExpectations(r.T())
+}
+
+func (r *execHandlerSuite) TestSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RunWithVersion", r.ctx, r.action, "go", r.args).Return(2, nil)
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.NoError(err)
+	r.Equal(2, code)
+}
+
+func (r *execHandlerSuite) TestCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(1, code)
+}
+
+func (r *execHandlerSuite) TestCheckLocalVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(1, code)
+}
+
+func (r *execHandlerSuite) TestRunWithVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RunWithVersion", r.ctx, r.action, "go", r.args).Return(-1, errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(-1, code)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+)
+
+type HookHandler interface {
+	Handle(ctx context.Context, shell domain.Shell) (string, error)
+}
+
+type hookHandler struct{}
+
+func NewHook() HookHandler {
+	return &hookHandler{}
+}
+
+func (r *hookHandler) Handle(ctx context.Context, shell domain.Shell) (string, error) {
+	slog.InfoContext(ctx, "Generating shell hook", slog.String("HookHandler", "Handle"), slog.String("shell", string(shell)))
+
+	if err := shell.CheckShell(); err != nil {
+		return "", err
+	}
+
+	return shell.Hook(), nil
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type HookHandlerMock struct {
+	mock.Mock
+}
+
+func (m *HookHandlerMock) Handle(ctx context.Context, shell domain.Shell) (string, error) {
+	args := m.Called(ctx, shell)
+	return args.String(0), args.Error(1)
+}
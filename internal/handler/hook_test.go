@@ -0,0 +1,44 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/stretchr/testify/suite"
+)
+
+type hookHandlerSuite struct {
+	suite.Suite
+	ctx     context.Context
+	handler handler.HookHandler
+}
+
+func TestHookHandler(t *testing.T) {
+	suite.Run(t, new(hookHandlerSuite))
+}
+
+func (r *hookHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.handler = handler.NewHook()
+}
+
+func (r *hookHandlerSuite) TestSuccess() {
+	// Act
+	script, err := r.handler.Handle(r.ctx, domain.ZshShell)
+
+	// Assert
+	r.NoError(err)
+	r.Equal(domain.ZshShell.Hook(), script)
+}
+
+func (r *hookHandlerSuite) TestInvalidShellError() {
+	// Act
+	script, err := r.handler.Handle(r.ctx, domain.Shell("csh"))
+
+	// Assert
+	r.Error(err)
+	r.Equal(domain.NewInvalidShellError(domain.Shell("csh")), err)
+	r.Empty(script)
+}
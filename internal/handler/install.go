@@ -3,11 +3,10 @@ package handler
 import (
 	"context"
 	"log/slog"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
 )
 
 type InstallHandler interface {
@@ -16,40 +15,70 @@ type InstallHandler interface {
 
 type installHandler struct {
 	sharedSvc service.SharedService
+	progress  util.Progress
 }
 
-func NewInstall(sharedHandler service.SharedService) InstallHandler {
+func NewInstall(sharedHandler service.SharedService, progress util.Progress) InstallHandler {
 	return &installHandler{
 		sharedSvc: sharedHandler,
+		progress:  progress,
 	}
 }
 
 func (r *installHandler) Handle(ctx context.Context, install *domain.Action) error {
 	slog.InfoContext(ctx, "Installing Go version", slog.String("InstallHandler", "Handle"), slog.String("version", install.Version))
 
-	spn := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	defer spn.Stop()
-	spn.Start()
-
-	steps := []struct {
-		message string
-		action  func() error
-	}{
-		{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, install) }},
-		{" Checking version...", func() error { return r.sharedSvc.CheckVersion(ctx, install) }},
-		{" Downloading files...", func() error { return r.sharedSvc.DownloadVersion(ctx, install) }},
-		{" Verifying checksum...", func() error { return r.sharedSvc.Checksum(ctx, install) }},
-		{" Removing previous version...", func() error { return r.sharedSvc.RemoveVersion(ctx, install) }},
-		{" Extracting files...", func() error { return r.sharedSvc.UntarFiles(ctx, install) }},
-		{" Adding to path...", func() error { return r.sharedSvc.AddToPath(ctx, install) }},
-	}
+	r.progress.Start()
+	defer r.progress.Stop()
 
-	for _, step := range steps {
-		spn.Suffix = step.message
-		if err := step.action(); err != nil {
-			return err
+	var steps []step
+	switch {
+	case install.FromSource():
+		// A build from source skips the release index and the cache, and is left in
+		// the staging directory like an extracted archive.
+		steps = []step{
+			{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, install) }, nil},
+			{" Finding bootstrap version...", func() error { return r.sharedSvc.FindBootstrap(ctx, install) }, nil},
+			{" Fetching source...", func() error { return r.sharedSvc.FetchSource(ctx, install) }, func() error { return r.sharedSvc.RemoveStagingDir(ctx, install) }},
+			{" Building from source...", func() error { return r.sharedSvc.BuildSource(ctx, install) }, nil},
+		}
+	case install.FromArchive():
+		// An archive given by the user skips the release index, and so the cache.
+		steps = []step{
+			{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, install) }, nil},
+			{" Downloading files...", func() error { return r.sharedSvc.DownloadArchive(ctx, install, r.progress.Transfer) }, func() error { return r.sharedSvc.RemoveURLDownload(ctx, install) }},
+			{" Checking archive...", func() error { return r.sharedSvc.CheckArchive(ctx, install) }, nil},
+			{" Extracting files...", func() error { return r.sharedSvc.UntarFiles(ctx, install, r.progress.Transfer) }, func() error { return r.sharedSvc.RemoveStagingDir(ctx, install) }},
+		}
+	default:
+		steps = []step{
+			{" Resolving version...", func() error { return r.sharedSvc.ResolveVersion(ctx, install) }, nil},
+			{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, install) }, nil},
+			{" Checking version...", func() error { return r.sharedSvc.CheckVersion(ctx, install) }, nil},
+			{" Downloading files...", func() error { return r.sharedSvc.DownloadVersion(ctx, install, r.progress.Transfer) }, func() error { return r.sharedSvc.RemoveStagingDir(ctx, install) }},
+			{" Verifying checksum...", func() error { return r.sharedSvc.Checksum(ctx, install) }, nil},
+			{" Verifying signature...", func() error { return r.sharedSvc.VerifySignature(ctx, install) }, nil},
+			{" Caching archive...", func() error { return r.sharedSvc.CacheArchive(ctx, install) }, nil},
+			{" Extracting files...", func() error { return r.sharedSvc.UntarFiles(ctx, install, r.progress.Transfer) }, func() error { return r.sharedSvc.RemoveStagingDir(ctx, install) }},
 		}
 	}
 
+	steps = append(steps, step{" Replacing previous installation...", func() error { return r.sharedSvc.InstallVersion(ctx, install) }, func() error { return r.sharedSvc.RestoreVersion(ctx, install) }})
+
+	// A toolchain for another platform can't run here, so it's only put in place.
+	if install.Target().IsHost() {
+		steps = append(steps, []step{
+			{" Setting current version...", func() error { return r.sharedSvc.SetCurrentVersion(ctx, install) }, func() error { return r.sharedSvc.RestoreCurrentVersion(ctx, install) }},
+			{" Creating shims...", func() error { return r.sharedSvc.CreateShims(ctx, install) }, nil},
+			{" Adding to path...", func() error { return r.sharedSvc.AddToPath(ctx, install) }, func() error { return r.sharedSvc.RestoreRunCommands(ctx, install) }},
+		}...)
+	}
+
+	steps = append(steps, step{" Cleaning up...", func() error { return r.sharedSvc.RemoveVersionBackup(ctx, install) }, nil})
+
+	if err := runTransaction(ctx, r.progress, steps); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -1,13 +1,17 @@
 package handler_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -30,7 +34,7 @@ func (r *installHandlerSuite) SetupTest() {
 		HomeDir: "/home/fake",
 	}
 	r.sharedSvc = new(service.SharedServiceMock)
-	r.handler = handler.NewInstall(r.sharedSvc)
+	r.handler = handler.NewInstall(r.sharedSvc, util.NewProgress(io.Discard, false))
 }
 
 func (r *installHandlerSuite) TearDownTest() {
@@ -39,13 +43,19 @@ func (r *installHandlerSuite) TearDownTest() {
 
 func (r *installHandlerSuite) TestSuccess() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("UntarFiles", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -54,8 +64,21 @@ func (r *installHandlerSuite) TestSuccess() {
 	r.NoError(err)
 }
 
+func (r *installHandlerSuite) TestResolveVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
 func (r *installHandlerSuite) TestCheckUserHomeError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(errors.New("error"))
 
 	// Act
@@ -68,6 +91,7 @@ func (r *installHandlerSuite) TestCheckUserHomeError() {
 
 func (r *installHandlerSuite) TestCheckVersionError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(errors.New("error"))
 
@@ -81,9 +105,10 @@ func (r *installHandlerSuite) TestCheckVersionError() {
 
 func (r *installHandlerSuite) TestDownloadVersionError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(errors.New("error"))
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -95,10 +120,30 @@ func (r *installHandlerSuite) TestDownloadVersionError() {
 
 func (r *installHandlerSuite) TestChecksumError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *installHandlerSuite) TestVerifySignatureError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -108,13 +153,16 @@ func (r *installHandlerSuite) TestChecksumError() {
 	r.Equal("error", err.Error())
 }
 
-func (r *installHandlerSuite) TestRemoveVersionError() {
+func (r *installHandlerSuite) TestCacheArchiveError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -126,12 +174,84 @@ func (r *installHandlerSuite) TestRemoveVersionError() {
 
 func (r *installHandlerSuite) TestUntarFilesError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *installHandlerSuite) TestInstallVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *installHandlerSuite) TestSetCurrentVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *installHandlerSuite) TestCreateShimsError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("UntarFiles", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -143,13 +263,235 @@ func (r *installHandlerSuite) TestUntarFilesError() {
 
 func (r *installHandlerSuite) TestAddToPathError() {
 	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("UntarFiles", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(errors.New("rollback error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *installHandlerSuite) TestRollbackInReverseOrder() {
+	// Arrange
+	var rollbacks []string
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(errors.New("error"))
+	for _, method := range []string{"RestoreRunCommands", "RestoreCurrentVersion", "RestoreVersion", "RemoveStagingDir"} {
+		r.sharedSvc.On(method, r.ctx, r.action).Return(nil).Run(func(mock.Arguments) { rollbacks = append(rollbacks, method) })
+	}
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal([]string{"RestoreRunCommands", "RestoreCurrentVersion", "RestoreVersion", "RemoveStagingDir", "RemoveStagingDir"}, rollbacks)
+}
+
+func (r *installHandlerSuite) TestCancelledRollsBack() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil).Once()
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "SetCurrentVersion", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestCancelledAfterExtractingRemovesStaging() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil).Twice()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "InstallVersion", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestSuccessFromArchive() {
+	// Arrange
+	r.action = &domain.Action{ArchiveURL: "https://example.com/go1.22.3.linux-amd64.tar.gz", HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadArchive", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("CheckArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.sharedSvc.AssertNotCalled(r.T(), "CheckVersion", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestCheckArchiveError() {
+	// Arrange
+	r.action = &domain.Action{Archive: "/tmp/go.tar.gz", HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadArchive", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("CheckArchive", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveURLDownload", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *installHandlerSuite) TestCancelledAfterDownloadingArchiveRemovesDownload() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.action = &domain.Action{ArchiveURL: "https://example.com/go1.22.3.linux-amd64.tar.gz", HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadArchive", r.ctx, r.action, mock.Anything).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RemoveURLDownload", r.ctx, r.action).Return(nil).Once()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "CheckArchive", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestCancelledAfterExtractingArchiveRemovesStaging() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.action = &domain.Action{ArchiveURL: "https://example.com/go1.22.3.linux-amd64.tar.gz", HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadArchive", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("CheckArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil).Once()
+	r.sharedSvc.On("RemoveURLDownload", r.ctx, r.action).Return(nil).Once()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "InstallVersion", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestSuccessForTarget() {
+	// Arrange
+	r.action = &domain.Action{Version: "go1.22.3", OS: "plan9", Arch: "arm", HomeDir: "/home/fake"}
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.sharedSvc.AssertNotCalled(r.T(), "SetCurrentVersion", r.ctx, r.action)
+	r.sharedSvc.AssertNotCalled(r.T(), "CreateShims", r.ctx, r.action)
+	r.sharedSvc.AssertNotCalled(r.T(), "AddToPath", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestSuccessFromSource() {
+	// Arrange
+	r.action = &domain.Action{Version: "gotip", Source: domain.TipRef, SourceRepo: domain.GoSourceRepo, HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("FindBootstrap", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("FetchSource", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("BuildSource", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.sharedSvc.AssertNotCalled(r.T(), "UntarFiles", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestBuildSourceError() {
+	// Arrange
+	r.action = &domain.Action{Version: "gotip", Source: domain.TipRef, SourceRepo: domain.GoSourceRepo, HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("FindBootstrap", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("FetchSource", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("BuildSource", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -157,4 +499,44 @@ func (r *installHandlerSuite) TestAddToPathError() {
 	// Assert
 	r.Error(err)
 	r.Equal("error", err.Error())
+	r.sharedSvc.AssertNotCalled(r.T(), "InstallVersion", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestCancelledAfterBuildingRemovesStaging() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.action = &domain.Action{Version: "gotip", Source: domain.TipRef, SourceRepo: domain.GoSourceRepo, HomeDir: "/home/fake"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("FindBootstrap", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("FetchSource", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("BuildSource", r.ctx, r.action).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil).Once()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "InstallVersion", r.ctx, r.action)
+}
+
+func (r *installHandlerSuite) TestDownloadProgress() {
+	// Arrange
+	var buf bytes.Buffer
+	r.handler = handler.NewInstall(r.sharedSvc, util.NewProgress(&buf, true))
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(errors.New("error")).Run(func(args mock.Arguments) {
+		args.Get(2).(func(int64, int64))(512, 1024)
+	})
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Contains(buf.String(), " Downloading files... [==========>         ]  50% 512 B/1.0 KiB")
 }
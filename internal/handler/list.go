@@ -2,16 +2,15 @@ package handler
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
-	"runtime"
-	"strings"
+	"slices"
 
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/service"
 )
 
 type ListHandler interface {
-	Handle(ctx context.Context) error
+	Handle(ctx context.Context, list *domain.Action) (domain.VersionListResult, error)
 }
 
 type listHandler struct {
@@ -24,38 +23,38 @@ func NewList(sharedSvc service.SharedService) ListHandler {
 	}
 }
 
-func (r *listHandler) Handle(ctx context.Context) error {
+func (r *listHandler) Handle(ctx context.Context, list *domain.Action) (domain.VersionListResult, error) {
 
 	slog.InfoContext(ctx, "Listing all Go versions", slog.String("ListHandler", "Handle"))
 
-	availableVersions, err := r.sharedSvc.GetAvailableGoVersions(ctx)
-	if err != nil {
-		return err
+	if err := r.sharedSvc.CheckUserHome(ctx, list); err != nil {
+		return domain.VersionListResult{}, err
 	}
 
-	installedVersion, _ := r.sharedSvc.GetInstalledGoVersion(ctx)
-
-	fmt.Println(strings.Repeat("=", 100))
-	fmt.Printf("Available Go versions for %s/%s \n", runtime.GOOS, runtime.GOARCH)
-	fmt.Println(strings.Repeat("=", 100))
+	availableVersions, err := r.sharedSvc.GetAvailableGoVersions(ctx, list)
+	if err != nil {
+		return domain.VersionListResult{}, err
+	}
 
-	numCols := 6
-	maxRows := (len(availableVersions.Versions) + numCols - 1) / numCols
+	// Only a toolchain of the host can be in use.
+	var installedVersion string
+	if list.Target().IsHost() {
+		installedVersion, _ = r.sharedSvc.GetCurrentGoVersion(ctx, list)
+	}
+	localVersions, _ := r.sharedSvc.GetLocalGoVersions(ctx, list)
 
-	for i := 0; i < maxRows; i++ {
-		var row []string
-		for j := 0; j < numCols; j++ {
-			idx := i + j*maxRows
-			if idx < len(availableVersions.Versions) {
-				row = append(row, fmt.Sprintf("%-15s", availableVersions.Versions[idx].String(installedVersion)))
-			}
+	result := domain.VersionListResult{
+		Platform: list.Target().String(),
+		Versions: make([]domain.VersionResult, len(availableVersions.Versions)),
+	}
+	for i, v := range availableVersions.Versions {
+		result.Versions[i] = domain.VersionResult{
+			Version:   v.Version,
+			Stable:    v.Stable,
+			Installed: slices.Contains(localVersions, v.Version),
+			Active:    v.Version == installedVersion,
 		}
-		fmt.Println(strings.Join(row, ""))
 	}
 
-	fmt.Println(strings.Repeat("=", 100))
-	fmt.Println("* currently in use")
-	fmt.Println(strings.Repeat("=", 100))
-
-	return nil
+	return result, nil
 }
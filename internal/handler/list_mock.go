@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 
+	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -10,7 +11,7 @@ type ListHandlerMock struct {
 	mock.Mock
 }
 
-func (m *ListHandlerMock) Handle(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *ListHandlerMock) Handle(ctx context.Context, list *domain.Action) (domain.VersionListResult, error) {
+	args := m.Called(ctx, list)
+	return args.Get(0).(domain.VersionListResult), args.Error(1)
 }
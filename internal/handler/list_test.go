@@ -3,15 +3,11 @@ package handler_test
 import (
 	"context"
 	"errors"
-	"fmt"
-	"runtime"
-	"strings"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/service"
-	"github.com/sbonaiva/govm/internal/test"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -37,50 +33,78 @@ func (r *listHandlerSuite) TearDownTest() {
 }
 
 func (r *listHandlerSuite) TestSuccess() {
-	r.sharedSvc.On("GetAvailableGoVersions", r.ctx).Return(domain.VersionsResponse{
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetAvailableGoVersions", r.ctx, &domain.Action{}).Return(domain.VersionsResponse{
+		Versions: []domain.VersionResponse{
+			{Version: "1.16", Stable: true},
+			{Version: "1.17", Stable: true},
+			{Version: "1.18", Stable: true},
+			{Version: "1.19", Stable: true},
+			{Version: "1.20", Stable: true},
+			{Version: "1.21rc1"},
+		},
+	}, nil)
+
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, &domain.Action{}).Return("1.20", nil)
+	r.sharedSvc.On("GetLocalGoVersions", r.ctx, &domain.Action{}).Return([]string{"1.17", "1.20"}, nil)
+
+	result, err := r.handler.Handle(r.ctx, &domain.Action{})
+
+	r.NoError(err)
+	r.Equal(domain.VersionListResult{
+		Platform: domain.HostPlatform().String(),
+		Versions: []domain.VersionResult{
+			{Version: "1.16", Stable: true},
+			{Version: "1.17", Stable: true, Installed: true},
+			{Version: "1.18", Stable: true},
+			{Version: "1.19", Stable: true},
+			{Version: "1.20", Stable: true, Installed: true, Active: true},
+			{Version: "1.21rc1"},
+		},
+	}, result)
+}
+
+func (r *listHandlerSuite) TestSuccessForTarget() {
+	list := &domain.Action{OS: "plan9", Arch: "arm"}
+	r.sharedSvc.On("CheckUserHome", r.ctx, list).Return(nil)
+	r.sharedSvc.On("GetAvailableGoVersions", r.ctx, list).Return(domain.VersionsResponse{
 		Versions: []domain.VersionResponse{
-			{Version: "1.16"},
-			{Version: "1.17"},
-			{Version: "1.18"},
 			{Version: "1.19"},
 			{Version: "1.20"},
-			{Version: "1.21"},
 		},
 	}, nil)
+	r.sharedSvc.On("GetLocalGoVersions", r.ctx, list).Return([]string{"1.20"}, nil)
 
-	r.sharedSvc.On("GetInstalledGoVersion", r.ctx).Return("", nil)
-
-	output, err := test.CaptureOutput(func() error {
-		err := r.handler.Handle(r.ctx)
-		return err
-	})
-
-	expected := strings.Join(
-		[]string{
-			strings.Repeat("=", 100) + "\n",
-			"Available Go versions for %s/%s \n",
-			strings.Repeat("=", 100) + "\n",
-			"1.16           1.17           1.18           1.19           1.20           1.21           \n",
-			strings.Repeat("=", 100) + "\n",
-			"* currently in use\n",
-			strings.Repeat("=", 100) + "\n",
-		},
-		"",
-	)
+	result, err := r.handler.Handle(r.ctx, list)
 
 	r.NoError(err)
-	r.Equal(fmt.Sprintf(expected, runtime.GOOS, runtime.GOARCH), output)
+	r.Equal(domain.VersionListResult{
+		Platform: "plan9/arm",
+		Versions: []domain.VersionResult{
+			{Version: "1.19"},
+			{Version: "1.20", Installed: true},
+		},
+	}, result)
+	r.sharedSvc.AssertNotCalled(r.T(), "GetCurrentGoVersion", r.ctx, list)
+}
+
+func (r *listHandlerSuite) TestCheckUserHomeError() {
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(errors.New("error"))
+
+	result, err := r.handler.Handle(r.ctx, &domain.Action{})
+
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Empty(result)
 }
 
 func (r *listHandlerSuite) TestError() {
-	r.sharedSvc.On("GetAvailableGoVersions", r.ctx).Return(domain.VersionsResponse{}, errors.New("error"))
+	r.sharedSvc.On("CheckUserHome", r.ctx, &domain.Action{}).Return(nil)
+	r.sharedSvc.On("GetAvailableGoVersions", r.ctx, &domain.Action{}).Return(domain.VersionsResponse{}, errors.New("error"))
 
-	output, err := test.CaptureOutput(func() error {
-		err := r.handler.Handle(r.ctx)
-		return err
-	})
+	result, err := r.handler.Handle(r.ctx, &domain.Action{})
 
 	r.Error(err)
 	r.Equal("error", err.Error())
-	r.Empty(output)
+	r.Empty(result)
 }
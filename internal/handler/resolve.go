@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/service"
+)
+
+type ResolveHandler interface {
+	Handle(ctx context.Context, resolve *domain.Action) error
+}
+
+type resolveHandler struct {
+	sharedSvc service.SharedService
+}
+
+func NewResolve(sharedSvc service.SharedService) ResolveHandler {
+	return &resolveHandler{
+		sharedSvc: sharedSvc,
+	}
+}
+
+func (r *resolveHandler) Handle(ctx context.Context, resolve *domain.Action) error {
+	slog.InfoContext(ctx, "Resolving pinned Go version", slog.String("ResolveHandler", "Handle"))
+
+	steps := []func() error{
+		func() error { return r.sharedSvc.ResolveVersion(ctx, resolve) },
+		func() error { return r.sharedSvc.CheckUserHome(ctx, resolve) },
+		func() error { return r.sharedSvc.CheckLocalVersion(ctx, resolve) },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
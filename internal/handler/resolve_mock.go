@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type ResolveHandlerMock struct {
+	mock.Mock
+}
+
+func (m *ResolveHandlerMock) Handle(ctx context.Context, resolve *domain.Action) error {
+	args := m.Called(ctx, resolve)
+	return args.Error(0)
+}
@@ -0,0 +1,87 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/stretchr/testify/suite"
+)
+
+type resolveHandlerSuite struct {
+	suite.Suite
+	ctx       context.Context
+	action    *domain.Action
+	sharedSvc *service.SharedServiceMock
+	handler   handler.ResolveHandler
+}
+
+func TestResolveHandler(t *testing.T) {
+	suite.Run(t, new(resolveHandlerSuite))
+}
+
+func (r *resolveHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.action = &domain.Action{}
+	r.sharedSvc = new(service.SharedServiceMock)
+	r.handler = handler.NewResolve(r.sharedSvc)
+}
+
+func (r *resolveHandlerSuite) TearDownTest() {
+	r.sharedSvc.AssertExpectations(r.T())
+}
+
+func (r *resolveHandlerSuite) TestSuccess() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+}
+
+func (r *resolveHandlerSuite) TestResolveVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *resolveHandlerSuite) TestCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *resolveHandlerSuite) TestCheckLocalVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/service"
+)
+
+type ShimHandler interface {
+	Handle(ctx context.Context, shim *domain.Action, name string, args []string) (int, error)
+}
+
+type shimHandler struct {
+	sharedSvc service.SharedService
+}
+
+func NewShim(sharedSvc service.SharedService) ShimHandler {
+	return &shimHandler{
+		sharedSvc: sharedSvc,
+	}
+}
+
+func (r *shimHandler) Handle(ctx context.Context, shim *domain.Action, name string, args []string) (int, error) {
+	slog.InfoContext(ctx, "Running shim", slog.String("ShimHandler", "Handle"), slog.String("name", name))
+
+	steps := []func() error{
+		func() error { return r.sharedSvc.CheckUserHome(ctx, shim) },
+		func() error { return r.sharedSvc.ResolveActiveVersion(ctx, shim) },
+		func() error { return r.sharedSvc.CheckLocalVersion(ctx, shim) },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return 1, err
+		}
+	}
+
+	return r.sharedSvc.RunWithVersion(ctx, shim, name, args)
+}
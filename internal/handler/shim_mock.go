@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type ShimHandlerMock struct {
+	mock.Mock
+}
+
+func (m *ShimHandlerMock) Handle(ctx context.Context, shim *domain.Action, name string, args []string) (int, error) {
+	a := m.Called(ctx, shim, name, args)
+	return a.Int(0), a.Error(1)
+}
@@ -0,0 +1,110 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/stretchr/testify/suite"
+)
+
+type shimHandlerSuite struct {
+	suite.Suite
+	ctx       context.Context
+	action    *domain.Action
+	args      []string
+	sharedSvc *service.SharedServiceMock
+	handler   handler.ShimHandler
+}
+
+func TestShimHandler(t *testing.T) {
+	suite.Run(t, new(shimHandlerSuite))
+}
+
+func (r *shimHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.action = &domain.Action{}
+	r.args = []string{"test", "./..."}
+	r.sharedSvc = new(service.SharedServiceMock)
+	r.handler = handler.NewShim(r.sharedSvc)
+}
+
+func (r *shimHandlerSuite) TearDownTest() {
+	r.sharedSvc.AssertExpectations(r.T())
+}
+
+func (r *shimHandlerSuite) TestSuccess() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("ResolveActiveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RunWithVersion", r.ctx, r.action, "go", r.args).Return(2, nil)
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.NoError(err)
+	r.Equal(2, code)
+}
+
+func (r *shimHandlerSuite) TestCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(1, code)
+}
+
+func (r *shimHandlerSuite) TestResolveActiveVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("ResolveActiveVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(1, code)
+}
+
+func (r *shimHandlerSuite) TestCheckLocalVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("ResolveActiveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(1, code)
+}
+
+func (r *shimHandlerSuite) TestRunWithVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("ResolveActiveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RunWithVersion", r.ctx, r.action, "go", r.args).Return(-1, errors.New("error"))
+
+	// Act
+	code, err := r.handler.Handle(r.ctx, r.action, "go", r.args)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+	r.Equal(-1, code)
+}
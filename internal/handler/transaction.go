@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/util"
+)
+
+// step is a unit of work of a handler. When rollback is set, it undoes action and is
+// called if a later step fails.
+type step struct {
+	message  string
+	action   func() error
+	rollback func() error
+}
+
+// runTransaction runs steps in order, showing their message on progress. When a step
+// fails, the rollbacks of the steps already completed are called in reverse order and
+// the error of the failed step is returned. Rollback failures are only logged. ctx is
+// checked before each step, so a cancelled transaction is rolled back the same way.
+func runTransaction(ctx context.Context, progress util.Progress, steps []step) error {
+	for i, s := range steps {
+		if err := ctx.Err(); err != nil {
+			slog.WarnContext(ctx, "Cancelled", slog.String("step", s.message), slog.String("error", err.Error()))
+			rollback(ctx, progress, steps[:i])
+			return domain.NewCancelledError()
+		}
+
+		progress.Step(s.message)
+		if err := s.action(); err != nil {
+			rollback(ctx, progress, steps[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func rollback(ctx context.Context, progress util.Progress, steps []step) {
+	progress.Step(" Rolling back...")
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].rollback == nil {
+			continue
+		}
+		if err := steps[i].rollback(); err != nil {
+			slog.WarnContext(ctx, "Rolling back", slog.String("step", steps[i].message), slog.String("error", err.Error()))
+		}
+	}
+}
@@ -3,11 +3,10 @@ package handler
 import (
 	"context"
 	"log/slog"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
 )
 
 type UninstallHandler interface {
@@ -16,53 +15,73 @@ type UninstallHandler interface {
 
 type uninstallHandler struct {
 	sharedSvc service.SharedService
+	progress  util.Progress
 }
 
-func NewUninstall(sharedHandler service.SharedService) UninstallHandler {
+func NewUninstall(sharedHandler service.SharedService, progress util.Progress) UninstallHandler {
 	return &uninstallHandler{
 		sharedSvc: sharedHandler,
+		progress:  progress,
 	}
 }
 
+// Handle removes uninstall.Version or, when not given, the current version. Only
+// removing the current version unlinks it and takes govm off the path; any other
+// version is just deleted.
 func (r *uninstallHandler) Handle(ctx context.Context, uninstall *domain.Action) error {
-	slog.InfoContext(ctx, "Uninstalling Go version", slog.String("UninstallHandler", "Handle"))
+	slog.InfoContext(ctx, "Uninstalling Go version", slog.String("UninstallHandler", "Handle"), slog.String("version", uninstall.Version))
 
-	spn := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	defer spn.Stop()
-	spn.Start()
+	r.progress.Start()
+	defer r.progress.Stop()
 
-	steps := []struct {
-		message string
-		action  func() error
-	}{
-		{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, uninstall) }},
-		{" Checking if Go is installed...", func() error { return r.checkIfGoIsInstalled(ctx) }},
-		{" Removing current version...", func() error { return r.sharedSvc.RemoveVersion(ctx, uninstall) }},
-		{" Removing from path...", func() error { return r.sharedSvc.RemoveFromPath(ctx, uninstall) }},
+	if err := runTransaction(ctx, r.progress, []step{
+		{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, uninstall) }, nil},
+		{" Checking if Go is installed...", func() error { return r.checkIfGoIsInstalled(ctx, uninstall) }, nil},
+	}); err != nil {
+		return err
 	}
 
-	for _, step := range steps {
-		spn.Suffix = step.message
-		if err := step.action(); err != nil {
-			return err
-		}
+	if uninstall.Version != uninstall.InstalledVersion {
+		return runTransaction(ctx, r.progress, []step{
+			{" Removing version...", func() error { return r.sharedSvc.BackupVersion(ctx, uninstall) }, func() error { return r.sharedSvc.RestoreVersion(ctx, uninstall) }},
+			{" Cleaning up...", func() error { return r.sharedSvc.RemoveVersionBackup(ctx, uninstall) }, nil},
+		})
 	}
 
-	return nil
+	return runTransaction(ctx, r.progress, []step{
+		{" Removing current version...", func() error { return r.sharedSvc.BackupVersion(ctx, uninstall) }, func() error { return r.sharedSvc.RestoreVersion(ctx, uninstall) }},
+		{" Unlinking current version...", func() error { return r.sharedSvc.RemoveCurrentVersion(ctx, uninstall) }, func() error { return r.sharedSvc.RestoreCurrentVersion(ctx, uninstall) }},
+		{" Removing from path...", func() error { return r.sharedSvc.RemoveFromPath(ctx, uninstall) }, func() error { return r.sharedSvc.RestoreRunCommands(ctx, uninstall) }},
+		{" Cleaning up...", func() error { return r.sharedSvc.RemoveVersionBackup(ctx, uninstall) }, nil},
+	})
 }
 
-func (r *uninstallHandler) checkIfGoIsInstalled(ctx context.Context) error {
+// checkIfGoIsInstalled sets uninstall.InstalledVersion to the current version and
+// defaults uninstall.Version to it. A version given explicitly must be installed. A
+// toolchain for another platform is never current.
+func (r *uninstallHandler) checkIfGoIsInstalled(ctx context.Context, uninstall *domain.Action) error {
 	slog.InfoContext(ctx, "Checking uninstall", slog.String("UninstallHandler", "checkIfGoInstalled"))
 
-	v, err := r.sharedSvc.GetInstalledGoVersion(ctx)
-	if err != nil {
-		slog.ErrorContext(ctx, "Error getting installed Go version", slog.String("UninstallHandler", "checkIfGoInstalled"), slog.String("error", err.Error()))
-		return err
+	if uninstall.Target().IsHost() {
+		v, err := r.sharedSvc.GetCurrentGoVersion(ctx, uninstall)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error getting current Go version", slog.String("UninstallHandler", "checkIfGoInstalled"), slog.String("error", err.Error()))
+			return err
+		}
+		uninstall.InstalledVersion = v
+	}
+
+	if uninstall.Version == "" {
+		if uninstall.InstalledVersion == "" {
+			slog.ErrorContext(ctx, "No Go installations found", slog.String("UninstallHandler", "checkIfGoInstalled"))
+			return domain.NewNoGoInstallationsFoundError()
+		}
+		uninstall.Version = uninstall.InstalledVersion
+		return nil
 	}
 
-	if v == "" {
-		slog.ErrorContext(ctx, "No Go installations found", slog.String("UninstallHandler", "checkIfGoInstalled"))
-		return domain.NewNoGoInstallationsFoundError()
+	if uninstall.Version == uninstall.InstalledVersion {
+		return nil
 	}
-	return nil
+	return r.sharedSvc.CheckLocalVersion(ctx, uninstall)
 }
@@ -3,11 +3,13 @@ package handler_test
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -30,7 +32,7 @@ func (r *uninstallHandlerSuite) SetupTest() {
 		HomeDir: "/home/fake",
 	}
 	r.sharedSvc = new(service.SharedServiceMock)
-	r.handler = handler.NewUninstall(r.sharedSvc)
+	r.handler = handler.NewUninstall(r.sharedSvc, util.NewProgress(io.Discard, false))
 }
 
 func (r *uninstallHandlerSuite) TearDownTest() {
@@ -40,9 +42,11 @@ func (r *uninstallHandlerSuite) TearDownTest() {
 func (r *uninstallHandlerSuite) TestSuccess() {
 	// Arrange
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("GetInstalledGoVersion", r.ctx).Return("1.20", nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("1.20.5", nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveCurrentVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("RemoveFromPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -66,7 +70,7 @@ func (r *uninstallHandlerSuite) TestCheckUserHomeError() {
 func (r *uninstallHandlerSuite) TestCheckIfGoInstalledError() {
 	// Arrange
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("GetInstalledGoVersion", r.ctx).Return("", errors.New("error"))
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("", errors.New("error"))
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -78,8 +82,9 @@ func (r *uninstallHandlerSuite) TestCheckIfGoInstalledError() {
 
 func (r *uninstallHandlerSuite) TestCheckIfGoInstalledEmpty() {
 	// Arrange
+	r.action.Version = ""
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("GetInstalledGoVersion", r.ctx).Return("", nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("", nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -89,11 +94,93 @@ func (r *uninstallHandlerSuite) TestCheckIfGoInstalledEmpty() {
 	r.Equal(domain.NewNoGoInstallationsFoundError(), err)
 }
 
-func (r *uninstallHandlerSuite) TestRemoveVersionError() {
+func (r *uninstallHandlerSuite) TestSuccessCurrentByDefault() {
 	// Arrange
+	r.action.Version = ""
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("GetInstalledGoVersion", r.ctx).Return("1.20", nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("1.20.5", nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveFromPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.Equal("1.20.5", r.action.Version)
+}
+
+func (r *uninstallHandlerSuite) TestSuccessNotCurrent() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("1.21.0", nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.sharedSvc.AssertNotCalled(r.T(), "RemoveCurrentVersion", r.ctx, r.action)
+	r.sharedSvc.AssertNotCalled(r.T(), "RemoveFromPath", r.ctx, r.action)
+}
+
+func (r *uninstallHandlerSuite) TestSuccessForTarget() {
+	// Arrange
+	r.action.OS = "plan9"
+	r.action.Arch = "arm"
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.sharedSvc.AssertNotCalled(r.T(), "GetCurrentGoVersion", r.ctx, r.action)
+}
+
+func (r *uninstallHandlerSuite) TestVersionNotInstalled() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("", nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(domain.NewVersionNotInstalledError("1.20.5"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewVersionNotInstalledError("1.20.5"), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "BackupVersion", r.ctx, r.action)
+}
+
+func (r *uninstallHandlerSuite) TestBackupVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("1.20.5", nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *uninstallHandlerSuite) TestRemoveCurrentVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("1.20.5", nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveCurrentVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
@@ -106,9 +193,12 @@ func (r *uninstallHandlerSuite) TestRemoveVersionError() {
 func (r *uninstallHandlerSuite) TestRemoveFromPathError() {
 	// Arrange
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("GetInstalledGoVersion", r.ctx).Return("1.20", nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("1.20.5", nil)
+	r.sharedSvc.On("BackupVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveCurrentVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("RemoveFromPath", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil)
 
 	// Act
 	err := r.handler.Handle(r.ctx, r.action)
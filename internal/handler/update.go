@@ -3,11 +3,10 @@ package handler
 import (
 	"context"
 	"log/slog"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
 )
 
 type UpdateHandler interface {
@@ -16,42 +15,42 @@ type UpdateHandler interface {
 
 type updateHandler struct {
 	sharedSvc service.SharedService
+	progress  util.Progress
 }
 
-func NewUpdate(sharedSvc service.SharedService) UpdateHandler {
+func NewUpdate(sharedSvc service.SharedService, progress util.Progress) UpdateHandler {
 	return &updateHandler{
 		sharedSvc: sharedSvc,
+		progress:  progress,
 	}
 }
 
 func (r *updateHandler) Handle(ctx context.Context, update *domain.Action) (string, error) {
 	slog.InfoContext(ctx, "Updating Go version", slog.String("UpdateHandler", "Handle"))
 
-	spn := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	defer spn.Stop()
-	spn.Start()
-
-	steps := []struct {
-		message string
-		action  func() error
-	}{
-		{" Checking update strategy...", func() error { return update.CheckUpdateStrategy() }},
-		{" Checking installed version...", func() error { return r.sharedSvc.CheckInstalledVersion(ctx, update) }},
-		{" Checking available updates...", func() error { return r.sharedSvc.CheckAvailableUpdates(ctx, update) }},
-		{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, update) }},
-		{" Checking version...", func() error { return r.sharedSvc.CheckVersion(ctx, update) }},
-		{" Downloading files...", func() error { return r.sharedSvc.DownloadVersion(ctx, update) }},
-		{" Verifying checksum...", func() error { return r.sharedSvc.Checksum(ctx, update) }},
-		{" Removing previous version...", func() error { return r.sharedSvc.RemoveVersion(ctx, update) }},
-		{" Extracting files...", func() error { return r.sharedSvc.UntarFiles(ctx, update) }},
-		{" Adding to path...", func() error { return r.sharedSvc.AddToPath(ctx, update) }},
+	r.progress.Start()
+	defer r.progress.Stop()
+
+	steps := []step{
+		{" Checking update strategy...", func() error { return update.CheckUpdateStrategy() }, nil},
+		{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, update) }, nil},
+		{" Checking installed version...", func() error { return r.sharedSvc.CheckInstalledVersion(ctx, update) }, nil},
+		{" Checking available updates...", func() error { return r.sharedSvc.CheckAvailableUpdates(ctx, update) }, nil},
+		{" Checking version...", func() error { return r.sharedSvc.CheckVersion(ctx, update) }, nil},
+		{" Downloading files...", func() error { return r.sharedSvc.DownloadVersion(ctx, update, r.progress.Transfer) }, func() error { return r.sharedSvc.RemoveStagingDir(ctx, update) }},
+		{" Verifying checksum...", func() error { return r.sharedSvc.Checksum(ctx, update) }, nil},
+		{" Verifying signature...", func() error { return r.sharedSvc.VerifySignature(ctx, update) }, nil},
+		{" Caching archive...", func() error { return r.sharedSvc.CacheArchive(ctx, update) }, nil},
+		{" Extracting files...", func() error { return r.sharedSvc.UntarFiles(ctx, update, r.progress.Transfer) }, func() error { return r.sharedSvc.RemoveStagingDir(ctx, update) }},
+		{" Replacing previous installation...", func() error { return r.sharedSvc.InstallVersion(ctx, update) }, func() error { return r.sharedSvc.RestoreVersion(ctx, update) }},
+		{" Setting current version...", func() error { return r.sharedSvc.SetCurrentVersion(ctx, update) }, func() error { return r.sharedSvc.RestoreCurrentVersion(ctx, update) }},
+		{" Creating shims...", func() error { return r.sharedSvc.CreateShims(ctx, update) }, nil},
+		{" Adding to path...", func() error { return r.sharedSvc.AddToPath(ctx, update) }, func() error { return r.sharedSvc.RestoreRunCommands(ctx, update) }},
+		{" Cleaning up...", func() error { return r.sharedSvc.RemoveVersionBackup(ctx, update) }, nil},
 	}
 
-	for _, step := range steps {
-		spn.Suffix = step.message
-		if err := step.action(); err != nil {
-			return "", err
-		}
+	if err := runTransaction(ctx, r.progress, steps); err != nil {
+		return "", err
 	}
 
 	return update.Version, nil
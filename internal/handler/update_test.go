@@ -3,11 +3,14 @@ package handler_test
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/handler"
 	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -31,7 +34,7 @@ func (r *updateHandlerSuite) SetupTest() {
 		UpdateStrategy: domain.PatchStrategy,
 	}
 	r.sharedSvc = new(service.SharedServiceMock)
-	r.handler = handler.NewUpdate(r.sharedSvc)
+	r.handler = handler.NewUpdate(r.sharedSvc, util.NewProgress(io.Discard, false))
 }
 
 func (r *updateHandlerSuite) TearDownTest() {
@@ -44,11 +47,16 @@ func (r *updateHandlerSuite) TestSuccess() {
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("UntarFiles", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveVersionBackup", r.ctx, r.action).Return(nil)
 
 	// Act
 	version, err := r.handler.Handle(r.ctx, r.action)
@@ -76,6 +84,7 @@ func (r *updateHandlerSuite) TestCheckUpdateStrategyError() {
 
 func (r *updateHandlerSuite) TestCheckInstalledVersionError() {
 	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(errors.New("error"))
 
 	// Act
@@ -89,6 +98,7 @@ func (r *updateHandlerSuite) TestCheckInstalledVersionError() {
 
 func (r *updateHandlerSuite) TestCheckAvailableUpdatesError() {
 	// Arrange
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(errors.New("error"))
 
@@ -103,8 +113,6 @@ func (r *updateHandlerSuite) TestCheckAvailableUpdatesError() {
 
 func (r *updateHandlerSuite) TestCheckUserHomeError() {
 	// Arrange
-	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(errors.New("error"))
 
 	// Act
@@ -138,7 +146,7 @@ func (r *updateHandlerSuite) TestDownloadVersionError() {
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(errors.New("error"))
 
 	// Act
 	version, err := r.handler.Handle(r.ctx, r.action)
@@ -155,8 +163,9 @@ func (r *updateHandlerSuite) TestChecksumError() {
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	version, err := r.handler.Handle(r.ctx, r.action)
@@ -167,15 +176,37 @@ func (r *updateHandlerSuite) TestChecksumError() {
 	r.Equal("error", err.Error())
 }
 
-func (r *updateHandlerSuite) TestRemoveVersionError() {
+func (r *updateHandlerSuite) TestVerifySignatureError() {
 	// Arrange
 	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	version, err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("", version)
+	r.Equal("error", err.Error())
+}
+
+func (r *updateHandlerSuite) TestCacheArchiveError() {
+	// Arrange
+	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	version, err := r.handler.Handle(r.ctx, r.action)
@@ -192,10 +223,112 @@ func (r *updateHandlerSuite) TestUntarFilesError() {
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	version, err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("", version)
+	r.Equal("error", err.Error())
+}
+
+func (r *updateHandlerSuite) TestCancelledAfterExtractingRemovesStaging() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil).Twice()
+
+	// Act
+	version, err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.Equal("", version)
+	r.sharedSvc.AssertNotCalled(r.T(), "InstallVersion", r.ctx, r.action)
+}
+
+func (r *updateHandlerSuite) TestInstallVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	version, err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("", version)
+	r.Equal("error", err.Error())
+}
+
+func (r *updateHandlerSuite) TestSetCurrentVersionError() {
+	// Arrange
+	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
+
+	// Act
+	version, err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("", version)
+	r.Equal("error", err.Error())
+}
+
+func (r *updateHandlerSuite) TestCreateShimsError() {
+	// Arrange
+	r.sharedSvc.On("CheckInstalledVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("UntarFiles", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	version, err := r.handler.Handle(r.ctx, r.action)
@@ -212,11 +345,18 @@ func (r *updateHandlerSuite) TestAddToPathError() {
 	r.sharedSvc.On("CheckAvailableUpdates", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("CheckVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("DownloadVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("DownloadVersion", r.ctx, r.action, mock.Anything).Return(nil)
 	r.sharedSvc.On("Checksum", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("RemoveVersion", r.ctx, r.action).Return(nil)
-	r.sharedSvc.On("UntarFiles", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("VerifySignature", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CacheArchive", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("UntarFiles", r.ctx, r.action, mock.Anything).Return(nil)
+	r.sharedSvc.On("InstallVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
 	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("RestoreVersion", r.ctx, r.action).Return(errors.New("rollback error"))
+	r.sharedSvc.On("RemoveStagingDir", r.ctx, r.action).Return(nil)
 
 	// Act
 	version, err := r.handler.Handle(r.ctx, r.action)
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
+)
+
+type UseHandler interface {
+	Handle(ctx context.Context, use *domain.Action) error
+}
+
+type useHandler struct {
+	sharedSvc service.SharedService
+	progress  util.Progress
+}
+
+func NewUse(sharedSvc service.SharedService, progress util.Progress) UseHandler {
+	return &useHandler{
+		sharedSvc: sharedSvc,
+		progress:  progress,
+	}
+}
+
+func (r *useHandler) Handle(ctx context.Context, use *domain.Action) error {
+	slog.InfoContext(ctx, "Switching Go version", slog.String("UseHandler", "Handle"), slog.String("version", use.Version))
+
+	r.progress.Start()
+	defer r.progress.Stop()
+
+	steps := []step{
+		{" Resolving version...", func() error { return r.sharedSvc.ResolveVersion(ctx, use) }, nil},
+		{" Getting home...", func() error { return r.sharedSvc.CheckUserHome(ctx, use) }, nil},
+		{" Checking installed versions...", func() error { return r.sharedSvc.CheckLocalVersion(ctx, use) }, nil},
+		{" Checking current version...", func() error { return r.checkCurrentVersion(ctx, use) }, nil},
+		{" Setting current version...", func() error { return r.sharedSvc.SetCurrentVersion(ctx, use) }, func() error { return r.sharedSvc.RestoreCurrentVersion(ctx, use) }},
+		{" Creating shims...", func() error { return r.sharedSvc.CreateShims(ctx, use) }, nil},
+		{" Adding to path...", func() error { return r.sharedSvc.AddToPath(ctx, use) }, func() error { return r.sharedSvc.RestoreRunCommands(ctx, use) }},
+	}
+
+	return runTransaction(ctx, r.progress, steps)
+}
+
+func (r *useHandler) checkCurrentVersion(ctx context.Context, use *domain.Action) error {
+	v, err := r.sharedSvc.GetCurrentGoVersion(ctx, use)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error getting current Go version", slog.String("UseHandler", "checkCurrentVersion"), slog.String("error", err.Error()))
+		return err
+	}
+
+	use.InstalledVersion = v
+	return nil
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type UseHandlerMock struct {
+	mock.Mock
+}
+
+func (m *UseHandlerMock) Handle(ctx context.Context, use *domain.Action) error {
+	args := m.Called(ctx, use)
+	return args.Error(0)
+}
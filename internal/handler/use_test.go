@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/handler"
+	"github.com/sbonaiva/govm/internal/service"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type useHandlerSuite struct {
+	suite.Suite
+	ctx       context.Context
+	action    *domain.Action
+	sharedSvc *service.SharedServiceMock
+	handler   handler.UseHandler
+}
+
+func TestUseHandler(t *testing.T) {
+	suite.Run(t, new(useHandlerSuite))
+}
+
+func (r *useHandlerSuite) SetupTest() {
+	r.ctx = context.Background()
+	r.action = &domain.Action{
+		Version: "go1.22.3",
+		HomeDir: "/home/fake",
+	}
+	r.sharedSvc = new(service.SharedServiceMock)
+	r.handler = handler.NewUse(r.sharedSvc, util.NewProgress(io.Discard, false))
+}
+
+func (r *useHandlerSuite) TearDownTest() {
+	r.sharedSvc.AssertExpectations(r.T())
+}
+
+func (r *useHandlerSuite) TestSuccess() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("go1.21.0", nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(nil)
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.NoError(err)
+	r.Equal("go1.21.0", r.action.InstalledVersion)
+}
+
+func (r *useHandlerSuite) TestResolveVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *useHandlerSuite) TestCheckUserHomeError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *useHandlerSuite) TestCheckLocalVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(domain.NewVersionNotInstalledError(r.action.Version))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal(domain.NewVersionNotInstalledError(r.action.Version), err)
+}
+
+func (r *useHandlerSuite) TestCheckCurrentVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("", errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *useHandlerSuite) TestSetCurrentVersionError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("go1.21.0", nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(errors.New("error"))
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *useHandlerSuite) TestCreateShimsError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("go1.21.0", nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil).Once()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *useHandlerSuite) TestAddToPathError() {
+	// Arrange
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("go1.21.0", nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CreateShims", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("AddToPath", r.ctx, r.action).Return(errors.New("error"))
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil).Once()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Error(err)
+	r.Equal("error", err.Error())
+}
+
+func (r *useHandlerSuite) TestCancelledRollsBack() {
+	// Arrange
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	r.ctx = ctx
+	r.sharedSvc.On("ResolveVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckUserHome", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("CheckLocalVersion", r.ctx, r.action).Return(nil)
+	r.sharedSvc.On("GetCurrentGoVersion", r.ctx, r.action).Return("go1.21.0", nil)
+	r.sharedSvc.On("SetCurrentVersion", r.ctx, r.action).Return(nil).Run(func(mock.Arguments) { cancel() })
+	r.sharedSvc.On("RestoreCurrentVersion", r.ctx, r.action).Return(nil).Once()
+
+	// Act
+	err := r.handler.Handle(r.ctx, r.action)
+
+	// Assert
+	r.Equal(domain.NewCancelledError(), err)
+	r.sharedSvc.AssertNotCalled(r.T(), "CreateShims", r.ctx, r.action)
+}
@@ -3,19 +3,33 @@ package service
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
+	"github.com/sbonaiva/govm/internal/util"
+)
+
+const (
+	releaseIndex  = "the Go release index"
+	goVersionFile = ".go-version"
+	goModFile     = "go.mod"
+	versionEnv    = "GOVM_VERSION"
 )
 
 var (
+	archiveVersion = regexp.MustCompile(`^go[0-9]+(\.[0-9]+)*((rc|beta)[0-9]+)?$`)
+
 	shellRunCommandFiles = map[string]string{
 		"/bin/bash":     ".bashrc",
 		"/usr/bin/bash": ".bashrc",
@@ -30,35 +44,67 @@ var (
 
 type SharedService interface {
 	CheckUserHome(ctx context.Context, action *domain.Action) error
+	ResolveVersion(ctx context.Context, action *domain.Action) error
+	ResolveActiveVersion(ctx context.Context, action *domain.Action) error
 	CheckVersion(ctx context.Context, action *domain.Action) error
-	DownloadVersion(ctx context.Context, action *domain.Action) error
+	CheckLocalVersion(ctx context.Context, action *domain.Action) error
+	DownloadVersion(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error
 	Checksum(ctx context.Context, action *domain.Action) error
-	RemoveVersion(ctx context.Context, action *domain.Action) error
-	UntarFiles(ctx context.Context, action *domain.Action) error
+	VerifySignature(ctx context.Context, action *domain.Action) error
+	DownloadArchive(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error
+	CheckArchive(ctx context.Context, action *domain.Action) error
+	CacheArchive(ctx context.Context, action *domain.Action) error
+	UntarFiles(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error
+	FindBootstrap(ctx context.Context, action *domain.Action) error
+	FetchSource(ctx context.Context, action *domain.Action) error
+	BuildSource(ctx context.Context, action *domain.Action) error
+	InstallVersion(ctx context.Context, action *domain.Action) error
+	BackupVersion(ctx context.Context, action *domain.Action) error
+	RestoreVersion(ctx context.Context, action *domain.Action) error
+	RemoveVersionBackup(ctx context.Context, action *domain.Action) error
+	RemoveStagingDir(ctx context.Context, action *domain.Action) error
+	RemoveURLDownload(ctx context.Context, action *domain.Action) error
+	SetCurrentVersion(ctx context.Context, action *domain.Action) error
+	RemoveCurrentVersion(ctx context.Context, action *domain.Action) error
+	RestoreCurrentVersion(ctx context.Context, action *domain.Action) error
+	CreateShims(ctx context.Context, action *domain.Action) error
+	RunWithVersion(ctx context.Context, action *domain.Action, name string, args []string) (int, error)
 	AddToPath(ctx context.Context, action *domain.Action) error
 	RemoveFromPath(ctx context.Context, action *domain.Action) error
+	RestoreRunCommands(ctx context.Context, action *domain.Action) error
 	CheckInstalledVersion(ctx context.Context, action *domain.Action) error
 	CheckAvailableUpdates(ctx context.Context, action *domain.Action) error
-	GetInstalledGoVersion(ctx context.Context) (string, error)
-	GetAvailableGoVersions(ctx context.Context) (domain.VersionsResponse, error)
+	GetAvailableGoVersions(ctx context.Context, action *domain.Action) (domain.VersionsResponse, error)
+	GetLocalGoVersions(ctx context.Context, action *domain.Action) ([]string, error)
+	GetCurrentGoVersion(ctx context.Context, action *domain.Action) (string, error)
+	GetCachedArchives(ctx context.Context, action *domain.Action) ([]domain.CachedArchive, error)
+	RemoveCachedArchive(ctx context.Context, action *domain.Action, archive domain.CachedArchive) error
+	CleanCache(ctx context.Context, action *domain.Action) error
+	ReadConfig(ctx context.Context, action *domain.Action) (domain.Config, error)
+	WriteConfig(ctx context.Context, action *domain.Action, config domain.Config) error
 }
 
 type sharedService struct {
 	httpGateway gateway.HttpGateway
 	osGateway   gateway.OsGateway
+	config      *domain.Config
 }
 
 type version struct {
 	Major int
 	Minor int
 	Patch int
-	Raw   string
+	// Pre is the kind of pre-release, "beta" or "rc", and is empty for a stable release.
+	Pre    string
+	PreNum int
+	Raw    string
 }
 
-func NewShared(httpGateway gateway.HttpGateway, osGateway gateway.OsGateway) SharedService {
+func NewShared(httpGateway gateway.HttpGateway, osGateway gateway.OsGateway, config *domain.Config) SharedService {
 	return &sharedService{
 		httpGateway: httpGateway,
 		osGateway:   osGateway,
+		config:      config,
 	}
 }
 
@@ -66,107 +112,819 @@ func (r *sharedService) CheckUserHome(ctx context.Context, action *domain.Action
 	homeDir, err := r.osGateway.GetUserHomeDir()
 	if err != nil {
 		slog.ErrorContext(ctx, "Getting current user", slog.String("SharedService", "CheckUserHome"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeCheckUserHome)
+		return domain.NewUnexpectedError(domain.ErrCodeCheckUserHome, err)
 	}
 	action.HomeDir = homeDir
+	action.Root = r.config.Root
+	return nil
+}
+
+func (r *sharedService) ResolveVersion(ctx context.Context, action *domain.Action) error {
+	if action.Version != "" {
+		return nil
+	}
+
+	wd, err := r.osGateway.GetWorkingDir()
+	if err != nil {
+		slog.ErrorContext(ctx, "Getting working directory", slog.String("SharedService", "ResolveVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeResolveWorkingDir, err)
+	}
+
+	v, err := r.findPinnedVersion(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	if v == "" {
+		return domain.NewNoPinnedVersionFoundError(wd)
+	}
+
+	action.Version = v
+	return nil
+}
+
+// ResolveActiveVersion picks the version a shim should run: the GOVM_VERSION
+// environment variable, then the version pinned for the working directory and
+// finally the version linked as current.
+func (r *sharedService) ResolveActiveVersion(ctx context.Context, action *domain.Action) error {
+	if action.Version != "" {
+		return nil
+	}
+
+	if v := r.osGateway.GetEnv(versionEnv); v != "" {
+		action.Version = r.normalizeVersion(v)
+		return nil
+	}
+
+	wd, err := r.osGateway.GetWorkingDir()
+	if err != nil {
+		slog.ErrorContext(ctx, "Getting working directory", slog.String("SharedService", "ResolveActiveVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeResolveWorkingDir, err)
+	}
+
+	v, err := r.findPinnedVersion(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	if v == "" {
+		if v, err = r.GetCurrentGoVersion(ctx, action); err != nil {
+			return err
+		}
+	}
+
+	if v == "" {
+		return domain.NewNoActiveVersionError()
+	}
+
+	action.Version = v
 	return nil
 }
 
+func (r *sharedService) findPinnedVersion(ctx context.Context, wd string) (string, error) {
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		for _, file := range []string{goVersionFile, goModFile} {
+			content, err := r.osGateway.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				slog.ErrorContext(ctx, "Reading version file", slog.String("SharedService", "findPinnedVersion"), slog.String("file", file), slog.String("error", err.Error()))
+				return "", domain.NewUnexpectedError(domain.ErrCodeResolveReadFile, err)
+			}
+
+			var v string
+			if file == goVersionFile {
+				v = r.parseGoVersionFile(string(content))
+			} else {
+				v = r.parseGoModFile(string(content))
+			}
+
+			if v != "" {
+				slog.InfoContext(ctx, "Version resolved", slog.String("SharedService", "findPinnedVersion"), slog.String("file", filepath.Join(dir, file)), slog.String("version", v))
+				return v, nil
+			}
+		}
+
+		if filepath.Dir(dir) == dir {
+			return "", nil
+		}
+	}
+}
+
+func (r *sharedService) parseGoVersionFile(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return r.normalizeVersion(line)
+		}
+	}
+	return ""
+}
+
+func (r *sharedService) parseGoModFile(content string) string {
+	var goDirective, toolchainDirective string
+
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			goDirective = fields[1]
+		case "toolchain":
+			if fields[1] != "default" {
+				toolchainDirective = fields[1]
+			}
+		}
+	}
+
+	if toolchainDirective != "" {
+		return r.normalizeVersion(toolchainDirective)
+	}
+
+	if goDirective != "" {
+		return r.normalizeVersion(goDirective)
+	}
+
+	return ""
+}
+
+// normalizeVersion converts the version formats accepted in .go-version and go.mod
+// files into a release name. Since Go 1.21 the first release of a minor version
+// is named "go1.N.0" instead of "go1.N".
+func (r *sharedService) normalizeVersion(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "go")
+
+	if parts := strings.Split(s, "."); len(parts) == 2 {
+		if minor, err := strconv.Atoi(parts[1]); err == nil && parts[0] == "1" && minor >= 21 {
+			s += ".0"
+		}
+	}
+
+	return "go" + s
+}
+
 func (r *sharedService) CheckVersion(ctx context.Context, action *domain.Action) error {
-	ok, err := r.httpGateway.VersionExists(ctx, action.Version)
+	ok, err := r.httpGateway.VersionExists(ctx, action.Version, action.Target())
 	if err != nil {
 		slog.ErrorContext(ctx, "Checking version", slog.String("SharedService", "CheckVersion"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeCheckVersion)
+		if errors.Is(err, gateway.ErrOffline) {
+			return domain.NewNotAvailableOfflineError(releaseIndex)
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeCheckVersion, err)
 	}
 
 	if !ok {
+		if target := action.Target(); !target.IsHost() {
+			return domain.NewVersionNotAvailableForError(action.Version, target)
+		}
 		return domain.NewVersionNotAvailableError(action.Version)
 	}
+
+	if !action.Unstable && r.parseVersion(action.Version).Pre != "" {
+		return domain.NewUnstableVersionError(action.Version)
+	}
+	return nil
+}
+
+func (r *sharedService) CheckLocalVersion(ctx context.Context, action *domain.Action) error {
+	if _, err := r.osGateway.Stat(action.HomeVersionDir()); err != nil {
+		if os.IsNotExist(err) {
+			return domain.NewVersionNotInstalledError(action.Version)
+		}
+		slog.ErrorContext(ctx, "Checking local version", slog.String("SharedService", "CheckLocalVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeCheckLocalVersion, err)
+	}
 	return nil
 }
 
-func (r *sharedService) DownloadVersion(ctx context.Context, action *domain.Action) error {
-	if err := r.osGateway.RemoveDir(action.DownloadFile()); err != nil {
-		slog.ErrorContext(ctx, "Removing previous download", slog.String("SharedService", "DownloadVersion"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeDownloadRemoveDir)
+// DownloadVersion fetches the archive of action.Version, unless an archive
+// matching its published checksum is already in the cache. A partial download
+// left by a previous run is resumed. progress, when not nil, is called as the
+// archive is received. With the cache off and no signature to verify, the archive
+// is extracted as it is received instead, see streamVersion.
+func (r *sharedService) DownloadVersion(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	expectedChecksum, err := r.httpGateway.GetChecksum(ctx, action.Version, action.Target())
+	if err != nil {
+		slog.ErrorContext(ctx, "Getting checksum", slog.String("SharedService", "DownloadVersion"), slog.String("error", err.Error()))
+		if errors.Is(err, gateway.ErrOffline) {
+			return domain.NewNotAvailableOfflineError(releaseIndex)
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeChecksumDownload, err)
+	}
+	action.Checksum = expectedChecksum
+
+	if checksum, err := r.fileChecksum(action.CacheFile()); err == nil && checksum == expectedChecksum {
+		slog.InfoContext(ctx, "Using cached archive", slog.String("SharedService", "DownloadVersion"), slog.String("file", action.CacheFile()))
+		action.Cached = true
+		return nil
+	}
+
+	if r.config.CachePolicy() == domain.OffCachePolicy && r.config.SignaturePolicy() == domain.OffSignaturePolicy {
+		return r.streamVersion(ctx, action, progress)
+	}
+
+	if err := r.osGateway.CreateDir(action.CacheDir(), 0755); err != nil {
+		slog.ErrorContext(ctx, "Creating cache directory", slog.String("SharedService", "DownloadVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeDownloadCreateDir, err)
 	}
 
-	file, err := r.osGateway.CreateFile(action.DownloadFile())
+	file, err := r.osGateway.AppendFile(action.DownloadFile())
 	if err != nil {
 		slog.ErrorContext(ctx, "Allocating resources", slog.String("SharedService", "DownloadVersion"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeDownloadCreateFile)
+		return domain.NewUnexpectedError(domain.ErrCodeDownloadCreateFile, err)
 	}
 	defer file.Close()
 
-	if err := r.httpGateway.DownloadVersion(ctx, action, file); err != nil {
+	if err := r.httpGateway.DownloadVersion(ctx, action, file, progress); err != nil {
 		slog.ErrorContext(ctx, "Downloading version", slog.String("SharedService", "DownloadVersion"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeDownloadVersion)
+		if errors.Is(err, gateway.ErrOffline) {
+			return domain.NewNotAvailableOfflineError(fmt.Sprintf("the archive of go version \"%s\"", action.Version))
+		}
+		if ctx.Err() != nil {
+			return domain.NewCancelledError()
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, err)
 	}
 
 	return nil
 }
 
 func (r *sharedService) Checksum(ctx context.Context, action *domain.Action) error {
-	expectedChecksum, err := r.httpGateway.GetChecksum(ctx, action.Version)
-	if err != nil {
-		slog.ErrorContext(ctx, "Getting checksum", slog.String("SharedService", "Checksum"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeChecksumDownload)
+	if action.Cached || action.Extracted {
+		return nil
 	}
 
 	file, err := r.osGateway.OpenFile(action.DownloadFile())
 	if err != nil {
 		slog.ErrorContext(ctx, "Opening file", slog.String("SharedService", "Checksum"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeChecksumOpenFile)
+		return domain.NewUnexpectedError(domain.ErrCodeChecksumOpenFile, err)
 	}
 	defer file.Close()
 
 	hash := sha256.New()
 	if _, err := io.Copy(hash, file); err != nil {
 		slog.ErrorContext(ctx, "Calculating checksum", slog.String("SharedService", "Checksum"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeChecksumCopy)
+		return domain.NewUnexpectedError(domain.ErrCodeChecksumCopy, err)
 	}
 
-	if expectedChecksum != fmt.Sprintf("%x", hash.Sum(nil)) {
+	if checksum := fmt.Sprintf("%x", hash.Sum(nil)); action.Checksum != checksum {
 		slog.ErrorContext(ctx, "Checksum does not match", slog.String("SharedService", "Checksum"))
-		return domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch)
+		if err := r.osGateway.RemoveFile(action.DownloadFile()); err != nil {
+			slog.WarnContext(ctx, "Removing download", slog.String("SharedService", "Checksum"), slog.String("error", err.Error()))
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, checksumMismatch(action.Checksum, checksum))
 	}
 
 	return nil
 }
 
-func (r *sharedService) RemoveVersion(ctx context.Context, action *domain.Action) error {
-	if err := r.osGateway.RemoveDir(action.HomeGoDir()); err != nil {
-		slog.ErrorContext(ctx, "Removing version", slog.String("SharedService", "RemoveVersion"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeRemoveVersion)
+// VerifySignature checks the downloaded archive against the signature published next
+// to it, as configured by the signature policy. Cached archives are verified again,
+// since they may have been cached while the policy was off.
+func (r *sharedService) VerifySignature(ctx context.Context, action *domain.Action) error {
+	policy := r.config.SignaturePolicy()
+	if policy == domain.OffSignaturePolicy {
+		return nil
+	}
+
+	err := r.verifySignature(ctx, action)
+	if err == nil {
+		return nil
 	}
+
+	if policy == domain.WarnSignaturePolicy {
+		slog.WarnContext(ctx, "Signature not verified", slog.String("SharedService", "VerifySignature"), slog.String("error", err.Error()))
+		action.SignatureUnverified = true
+		return nil
+	}
+
+	slog.ErrorContext(ctx, "Signature not verified", slog.String("SharedService", "VerifySignature"), slog.String("error", err.Error()))
+	return domain.NewSignatureNotVerifiedError(action.Filename(), err)
+}
+
+func (r *sharedService) verifySignature(ctx context.Context, action *domain.Action) error {
+	signature, err := r.httpGateway.GetSignature(ctx, action)
+	if err != nil {
+		return err
+	}
+
+	archive := action.DownloadFile()
+	if action.Cached {
+		archive = action.CacheFile()
+	}
+
+	file, err := r.osGateway.OpenFile(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return util.VerifySignature(util.GoReleaseKey, file, signature)
+}
+
+// CacheArchive moves a verified download into the cache, keyed by its checksum.
+func (r *sharedService) CacheArchive(ctx context.Context, action *domain.Action) error {
+	if action.Cached || action.Extracted {
+		return nil
+	}
+
+	if err := r.osGateway.CreateDir(action.CacheArchiveDir(), 0755); err != nil {
+		slog.ErrorContext(ctx, "Creating cache directory", slog.String("SharedService", "CacheArchive"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeCacheCreateDir, err)
+	}
+
+	if err := r.osGateway.Rename(action.DownloadFile(), action.CacheFile()); err != nil {
+		slog.ErrorContext(ctx, "Moving archive to cache", slog.String("SharedService", "CacheArchive"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeCacheStore, err)
+	}
+
+	action.Cached = true
 	return nil
 }
 
-func (r *sharedService) UntarFiles(ctx context.Context, action *domain.Action) error {
-	if err := r.osGateway.CreateDir(action.HomeGovmDir(), 0755); err != nil {
-		slog.ErrorContext(ctx, "Creating directory", slog.String("SharedService", "UntarFiles"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeUntarCreateDir)
+// checksumMismatch is the cause of a checksum mismatch, as shown with --verbose.
+func checksumMismatch(expected, actual string) error {
+	return fmt.Errorf("expected checksum %s, got %s", expected, actual)
+}
+
+func (r *sharedService) fileChecksum(path string) (string, error) {
+	file, err := r.osGateway.OpenFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// DownloadArchive downloads the archive of action.ArchiveURL, if any, and makes it
+// the archive of action. progress, when not nil, is called as it is received.
+func (r *sharedService) DownloadArchive(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	if action.ArchiveURL == "" {
+		return nil
+	}
+
+	if err := r.osGateway.CreateDir(action.CacheDir(), 0755); err != nil {
+		slog.ErrorContext(ctx, "Creating cache directory", slog.String("SharedService", "DownloadArchive"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeDownloadCreateDir, err)
+	}
+
+	file, err := r.osGateway.CreateFile(action.URLDownloadFile())
+	if err != nil {
+		slog.ErrorContext(ctx, "Allocating resources", slog.String("SharedService", "DownloadArchive"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeDownloadCreateFile, err)
+	}
+	defer file.Close()
+
+	if err := r.httpGateway.DownloadURL(ctx, action.ArchiveURL, file, progress); err != nil {
+		slog.ErrorContext(ctx, "Downloading archive", slog.String("SharedService", "DownloadArchive"), slog.String("url", action.ArchiveURL), slog.String("error", err.Error()))
+		r.RemoveURLDownload(ctx, action)
+		if errors.Is(err, gateway.ErrOffline) {
+			return domain.NewNotAvailableOfflineError(fmt.Sprintf("\"%s\"", action.ArchiveURL))
+		}
+		if ctx.Err() != nil {
+			return domain.NewCancelledError()
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, err)
 	}
 
-	if err := r.osGateway.Untar(action.DownloadFile(), action.HomeGovmDir()); err != nil {
+	action.Archive = action.URLDownloadFile()
+	return nil
+}
+
+// CheckArchive sets action.Version from the VERSION file of action.Archive, checks the
+// archive is built for action.Target() and, when action.Checksum is set, verifies the
+// archive against it.
+func (r *sharedService) CheckArchive(ctx context.Context, action *domain.Action) error {
+	name := action.Archive
+	if action.ArchiveURL != "" {
+		name = action.ArchiveURL
+	}
+
+	content, err := r.osGateway.ReadArchiveFile(action.Archive, "VERSION")
+	if err != nil {
+		slog.ErrorContext(ctx, "Reading version", slog.String("SharedService", "CheckArchive"), slog.String("archive", action.Archive), slog.String("error", err.Error()))
+		r.RemoveURLDownload(ctx, action)
+		return domain.NewInvalidArchiveError(name, err)
+	}
+
+	version, _, _ := strings.Cut(string(content), "\n")
+	version = strings.TrimSpace(version)
+	if !archiveVersion.MatchString(version) {
+		slog.ErrorContext(ctx, "Invalid version", slog.String("SharedService", "CheckArchive"), slog.String("archive", action.Archive), slog.String("version", version))
+		r.RemoveURLDownload(ctx, action)
+		return domain.NewInvalidArchiveError(name, fmt.Errorf("unexpected version %q in VERSION file", version))
+	}
+	action.Version = version
+
+	platform, err := r.osGateway.ReadArchivePlatform(action.Archive)
+	if err != nil {
+		slog.ErrorContext(ctx, "Reading platform", slog.String("SharedService", "CheckArchive"), slog.String("archive", action.Archive), slog.String("error", err.Error()))
+		r.RemoveURLDownload(ctx, action)
+		return domain.NewInvalidArchiveError(name, err)
+	}
+	if platform != action.Target() {
+		slog.ErrorContext(ctx, "Unexpected platform", slog.String("SharedService", "CheckArchive"), slog.String("archive", action.Archive), slog.String("platform", platform.String()))
+		r.RemoveURLDownload(ctx, action)
+		return domain.NewArchivePlatformError(name, platform, action.Target())
+	}
+
+	if action.Checksum == "" {
+		return nil
+	}
+
+	checksum, err := r.fileChecksum(action.Archive)
+	if err != nil {
+		slog.ErrorContext(ctx, "Calculating checksum", slog.String("SharedService", "CheckArchive"), slog.String("error", err.Error()))
+		r.RemoveURLDownload(ctx, action)
+		return domain.NewUnexpectedError(domain.ErrCodeChecksumCopy, err)
+	}
+
+	if checksum != action.Checksum {
+		slog.ErrorContext(ctx, "Checksum does not match", slog.String("SharedService", "CheckArchive"), slog.String("expected", action.Checksum), slog.String("actual", checksum))
+		r.RemoveURLDownload(ctx, action)
+		return domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, checksumMismatch(action.Checksum, checksum))
+	}
+
+	return nil
+}
+
+// RemoveURLDownload removes the archive downloaded by DownloadArchive, if any. A
+// failure is only logged, since the download is only a leftover then.
+func (r *sharedService) RemoveURLDownload(ctx context.Context, action *domain.Action) error {
+	if action.ArchiveURL == "" {
+		return nil
+	}
+	if err := r.osGateway.RemoveFile(action.URLDownloadFile()); err != nil && !os.IsNotExist(err) {
+		slog.WarnContext(ctx, "Removing download", slog.String("SharedService", "RemoveURLDownload"), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// RemoveStagingDir removes what was extracted or built into the staging directory,
+// when a later step fails or the action is cancelled before it is installed.
+func (r *sharedService) RemoveStagingDir(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+		slog.ErrorContext(ctx, "Removing staging directory", slog.String("SharedService", "RemoveStagingDir"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRollback, err)
+	}
+	return nil
+}
+
+// errDownloadFailed ends the extraction of a streamed archive whose download failed.
+var errDownloadFailed = errors.New("download failed")
+
+// streamVersion extracts the archive of action.Version into the staging directory as
+// it is downloaded, hashing it on the way, so that it's never written to disk. The
+// staging directory is removed when the download, the extraction or the checksum fails.
+func (r *sharedService) streamVersion(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	if err := r.createStagingDir(ctx, action); err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	reader, writer := io.Pipe()
+	extracted := make(chan error, 1)
+	go func() {
+		err := r.osGateway.UntarReader(ctx, reader, action.HomeVersionStagingDir())
+		if err == nil {
+			// The rest of the archive, e.g. the padding after the end of the tarball, is
+			// still part of the checksum.
+			_, err = io.Copy(io.Discard, reader)
+		}
+		reader.CloseWithError(err)
+		extracted <- err
+	}()
+
+	downloadErr := r.httpGateway.StreamVersion(ctx, action, io.MultiWriter(hash, writer), progress)
+	if downloadErr != nil {
+		writer.CloseWithError(errDownloadFailed)
+	} else {
+		writer.Close()
+	}
+	extractErr := <-extracted
+
+	var err error
+	switch {
+	case ctx.Err() != nil:
+		err = domain.NewCancelledError()
+	case extractErr != nil && !errors.Is(extractErr, errDownloadFailed):
+		slog.ErrorContext(ctx, "Extracting files", slog.String("SharedService", "streamVersion"), slog.String("error", extractErr.Error()))
+		err = domain.NewUnexpectedError(domain.ErrCodeUntarExtract, extractErr)
+	case errors.Is(downloadErr, gateway.ErrOffline):
+		err = domain.NewNotAvailableOfflineError(fmt.Sprintf("the archive of go version \"%s\"", action.Version))
+	case downloadErr != nil:
+		slog.ErrorContext(ctx, "Downloading version", slog.String("SharedService", "streamVersion"), slog.String("error", downloadErr.Error()))
+		err = domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, downloadErr)
+	default:
+		if checksum := fmt.Sprintf("%x", hash.Sum(nil)); action.Checksum != checksum {
+			slog.ErrorContext(ctx, "Checksum does not match", slog.String("SharedService", "streamVersion"))
+			err = domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, checksumMismatch(action.Checksum, checksum))
+		}
+	}
+
+	if err != nil {
+		if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+			slog.WarnContext(ctx, "Removing staging directory", slog.String("SharedService", "streamVersion"), slog.String("error", err.Error()))
+		}
+		return err
+	}
+
+	action.Extracted = true
+	return nil
+}
+
+// createStagingDir replaces the staging directory of action with an empty one.
+func (r *sharedService) createStagingDir(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+		slog.ErrorContext(ctx, "Removing staging directory", slog.String("SharedService", "createStagingDir"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeUntarCreateDir, err)
+	}
+
+	if err := r.osGateway.CreateDir(action.HomeVersionStagingDir(), 0755); err != nil {
+		slog.ErrorContext(ctx, "Creating directory", slog.String("SharedService", "createStagingDir"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeUntarCreateDir, err)
+	}
+
+	return nil
+}
+
+// UntarFiles extracts the archive of action, or the cached one, into the staging
+// directory, leaving the installed version untouched until InstallVersion. progress,
+// when not nil, is called with the bytes of the archive read so far and its size.
+func (r *sharedService) UntarFiles(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	if action.Extracted {
+		return nil
+	}
+
+	source := action.CacheFile()
+	if action.Archive != "" {
+		source = action.Archive
+		defer r.RemoveURLDownload(ctx, action)
+	}
+
+	if err := r.createStagingDir(ctx, action); err != nil {
+		return err
+	}
+
+	if err := r.osGateway.Untar(ctx, source, action.HomeVersionStagingDir(), progress); err != nil {
 		slog.ErrorContext(ctx, "Extracting files", slog.String("SharedService", "UntarFiles"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeUntarExtract)
+		if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+			slog.WarnContext(ctx, "Removing staging directory", slog.String("SharedService", "UntarFiles"), slog.String("error", err.Error()))
+		}
+		if ctx.Err() != nil {
+			return domain.NewCancelledError()
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeUntarExtract, err)
+	}
+
+	if r.config.CachePolicy() == domain.OffCachePolicy && action.Archive == "" {
+		if err := r.osGateway.RemoveDir(action.CacheArchiveDir()); err != nil {
+			slog.WarnContext(ctx, "Removing cached archive", slog.String("SharedService", "UntarFiles"), slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// FindBootstrap sets action.Bootstrap to the installed version action.Source is built
+// with: the current version or, when there is none, the newest one installed.
+func (r *sharedService) FindBootstrap(ctx context.Context, action *domain.Action) error {
+	bootstrap, err := r.GetCurrentGoVersion(ctx, action)
+	if err != nil {
+		return err
+	}
+
+	if bootstrap == "" {
+		local, err := r.GetLocalGoVersions(ctx, action)
+		if err != nil {
+			return err
+		}
+		for _, v := range local {
+			if bootstrap == "" || r.compareVersionDesc(r.parseVersion(v), r.parseVersion(bootstrap)) {
+				bootstrap = v
+			}
+		}
+	}
+
+	if bootstrap == "" {
+		return domain.NewNoBootstrapVersionError()
+	}
+
+	action.Bootstrap = filepath.Join(action.HomeVersionsDir(), bootstrap)
+	return nil
+}
+
+// FetchSource checks out action.Source of action.SourceRepo into the staging directory,
+// which BuildSource then builds in place.
+func (r *sharedService) FetchSource(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+		slog.ErrorContext(ctx, "Removing staging directory", slog.String("SharedService", "FetchSource"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeSourceCreateDir, err)
+	}
+
+	output, err := r.osGateway.FetchSource(ctx, action.SourceRepo, action.Source, action.HomeVersionStagingDir())
+	if err != nil {
+		slog.ErrorContext(ctx, "Fetching source", slog.String("SharedService", "FetchSource"), slog.String("repo", action.SourceRepo), slog.String("ref", action.Source), slog.String("output", string(output)), slog.String("error", err.Error()))
+		if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+			slog.WarnContext(ctx, "Removing staging directory", slog.String("SharedService", "FetchSource"), slog.String("error", err.Error()))
+		}
+		if ctx.Err() != nil {
+			return domain.NewCancelledError()
+		}
+		return domain.NewSourceFetchError(action.Source, action.SourceRepo, err)
+	}
+
+	return nil
+}
+
+// BuildSource runs make.bash in the staging directory with action.Bootstrap, leaving a
+// complete installation to rename into place like an extracted archive.
+func (r *sharedService) BuildSource(ctx context.Context, action *domain.Action) error {
+	output, err := r.osGateway.BuildSource(ctx, action.HomeVersionStagingDir(), action.Bootstrap)
+	if err != nil {
+		slog.ErrorContext(ctx, "Building source", slog.String("SharedService", "BuildSource"), slog.String("bootstrap", action.Bootstrap), slog.String("output", string(output)), slog.String("error", err.Error()))
+		if err := r.osGateway.RemoveDir(action.HomeVersionStagingDir()); err != nil {
+			slog.WarnContext(ctx, "Removing staging directory", slog.String("SharedService", "BuildSource"), slog.String("error", err.Error()))
+		}
+		if ctx.Err() != nil {
+			return domain.NewCancelledError()
+		}
+		return domain.NewSourceBuildError(action.Source, err)
+	}
+
+	slog.InfoContext(ctx, "Built source", slog.String("SharedService", "BuildSource"), slog.String("ref", action.Source), slog.String("output", string(output)))
+	return nil
+}
+
+// InstallVersion moves the installed version aside and renames the staging directory
+// into its place.
+func (r *sharedService) InstallVersion(ctx context.Context, action *domain.Action) error {
+	if err := r.BackupVersion(ctx, action); err != nil {
+		return err
+	}
+
+	if err := r.osGateway.Rename(action.HomeVersionStagingDir(), action.HomeVersionDir()); err != nil {
+		slog.ErrorContext(ctx, "Moving staged version", slog.String("SharedService", "InstallVersion"), slog.String("error", err.Error()))
+		if err := r.RestoreVersion(ctx, action); err != nil {
+			slog.WarnContext(ctx, "Restoring previous version", slog.String("SharedService", "InstallVersion"), slog.String("error", err.Error()))
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeInstallVersion, err)
+	}
+
+	return nil
+}
+
+// BackupVersion moves the installed version, if any, to the backup directory, from
+// where RestoreVersion can bring it back.
+func (r *sharedService) BackupVersion(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.HomeVersionBackupDir()); err != nil {
+		slog.ErrorContext(ctx, "Removing backup directory", slog.String("SharedService", "BackupVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveVersion, err)
+	}
+
+	if err := r.osGateway.Rename(action.HomeVersionDir(), action.HomeVersionBackupDir()); err != nil && !os.IsNotExist(err) {
+		slog.ErrorContext(ctx, "Moving version to backup", slog.String("SharedService", "BackupVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveVersion, err)
+	}
+
+	return nil
+}
+
+func (r *sharedService) RestoreVersion(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.HomeVersionDir()); err != nil {
+		slog.ErrorContext(ctx, "Removing version", slog.String("SharedService", "RestoreVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRollback, err)
+	}
+
+	if err := r.osGateway.Rename(action.HomeVersionBackupDir(), action.HomeVersionDir()); err != nil && !os.IsNotExist(err) {
+		slog.ErrorContext(ctx, "Moving backup to version", slog.String("SharedService", "RestoreVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRollback, err)
+	}
+
+	return nil
+}
+
+// RemoveVersionBackup drops the backup once the action succeeded. A failure is only
+// logged, since the action itself is complete.
+func (r *sharedService) RemoveVersionBackup(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.HomeVersionBackupDir()); err != nil {
+		slog.WarnContext(ctx, "Removing backup directory", slog.String("SharedService", "RemoveVersionBackup"), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+func (r *sharedService) SetCurrentVersion(ctx context.Context, action *domain.Action) error {
+	action.PreviousCurrent, _ = r.osGateway.ReadSymlink(action.HomeCurrentDir())
+
+	if err := r.osGateway.CreateSymlink(action.HomeVersionDir(), action.HomeCurrentDir()); err != nil {
+		slog.ErrorContext(ctx, "Linking current version", slog.String("SharedService", "SetCurrentVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeSetCurrentVersion, err)
+	}
+	return nil
+}
+
+func (r *sharedService) RemoveCurrentVersion(ctx context.Context, action *domain.Action) error {
+	action.PreviousCurrent, _ = r.osGateway.ReadSymlink(action.HomeCurrentDir())
+
+	if err := r.osGateway.RemoveFile(action.HomeCurrentDir()); err != nil && !os.IsNotExist(err) {
+		slog.ErrorContext(ctx, "Unlinking current version", slog.String("SharedService", "RemoveCurrentVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveCurrentVersion, err)
+	}
+	return nil
+}
+
+// RestoreCurrentVersion points the current link back to where it pointed before
+// SetCurrentVersion or RemoveCurrentVersion.
+func (r *sharedService) RestoreCurrentVersion(ctx context.Context, action *domain.Action) error {
+	if action.PreviousCurrent == "" {
+		if err := r.osGateway.RemoveFile(action.HomeCurrentDir()); err != nil && !os.IsNotExist(err) {
+			slog.ErrorContext(ctx, "Unlinking current version", slog.String("SharedService", "RestoreCurrentVersion"), slog.String("error", err.Error()))
+			return domain.NewUnexpectedError(domain.ErrCodeRollback, err)
+		}
+		return nil
+	}
+
+	if err := r.osGateway.CreateSymlink(action.PreviousCurrent, action.HomeCurrentDir()); err != nil {
+		slog.ErrorContext(ctx, "Linking current version", slog.String("SharedService", "RestoreCurrentVersion"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeRollback, err)
+	}
+	return nil
+}
+
+func (r *sharedService) CreateShims(ctx context.Context, action *domain.Action) error {
+	executable, err := r.osGateway.GetExecutable()
+	if err != nil {
+		slog.ErrorContext(ctx, "Getting executable", slog.String("SharedService", "CreateShims"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeGetExecutable, err)
 	}
 
-	defer r.osGateway.RemoveFile(action.DownloadFile())
+	if err := r.osGateway.CreateDir(action.HomeShimsDir(), 0755); err != nil {
+		slog.ErrorContext(ctx, "Creating directory", slog.String("SharedService", "CreateShims"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeCreateShims, err)
+	}
+
+	for _, shim := range domain.Shims {
+		if err := r.osGateway.CreateSymlink(executable, filepath.Join(action.HomeShimsDir(), shim)); err != nil {
+			slog.ErrorContext(ctx, "Linking shim", slog.String("SharedService", "CreateShims"), slog.String("shim", shim), slog.String("error", err.Error()))
+			return domain.NewUnexpectedError(domain.ErrCodeCreateShims, err)
+		}
+	}
 
 	return nil
 }
 
+func (r *sharedService) RunWithVersion(ctx context.Context, action *domain.Action, name string, args []string) (int, error) {
+	env := []string{
+		fmt.Sprintf("GOROOT=%s", action.HomeVersionDir()),
+		fmt.Sprintf("PATH=%s%c%s", action.HomeVersionBinDir(), os.PathListSeparator, r.osGateway.GetEnv("PATH")),
+	}
+
+	command := name
+	if slices.Contains(domain.Shims, name) {
+		command = filepath.Join(action.HomeVersionBinDir(), name)
+	}
+
+	code, err := r.osGateway.RunCommand(command, args, env)
+	if err != nil {
+		slog.ErrorContext(ctx, "Running command", slog.String("SharedService", "RunWithVersion"), slog.String("command", command), slog.String("error", err.Error()))
+		return code, domain.NewUnexpectedError(domain.ErrCodeRunCommand, err)
+	}
+
+	return code, nil
+}
+
 func (r *sharedService) AddToPath(ctx context.Context, action *domain.Action) error {
-	if path := r.osGateway.GetEnv("PATH"); strings.Contains(path, action.HomeGoBinDir()) {
+	if r.config.Shell() == domain.NoShellIntegration {
+		slog.InfoContext(ctx, "Shell integration disabled", slog.String("SharedService", "AddToPath"))
+		return nil
+	}
+
+	if path := r.osGateway.GetEnv("PATH"); strings.Contains(path, action.HomeShimsDir()) {
 		slog.InfoContext(ctx, "Go is already in PATH", slog.String("SharedService", "AddToPath"))
 		return nil
 	}
 
 	if shell := r.osGateway.GetEnv("SHELL"); shell != "" {
 		if rcf, exists := shellRunCommandFiles[shell]; exists {
-			return r.addToShellRunCommands(ctx, action, rcf)
+			if err := r.addToShellRunCommands(ctx, action, rcf); err != nil {
+				return err
+			}
+			action.PathUpdated = true
+			return nil
 		}
 	}
 
@@ -180,14 +938,20 @@ func (r *sharedService) AddToPath(ctx context.Context, action *domain.Action) er
 
 	if succeded == 0 {
 		slog.ErrorContext(ctx, "No shell rc file found", slog.String("SharedService", "AddToPath"))
-		return domain.NewUnexpectedError(domain.ErrCodeAddToPathNoShellsFound)
+		return domain.NewUnexpectedError(domain.ErrCodeAddToPathNoShellsFound, nil)
 	}
 
+	action.PathUpdated = true
 	return nil
 }
 
 func (r *sharedService) RemoveFromPath(ctx context.Context, action *domain.Action) error {
-	if path := r.osGateway.GetEnv("PATH"); !strings.Contains(path, action.HomeGoBinDir()) {
+	if r.config.Shell() == domain.NoShellIntegration {
+		slog.InfoContext(ctx, "Shell integration disabled", slog.String("SharedService", "RemoveFromPath"))
+		return nil
+	}
+
+	if path := r.osGateway.GetEnv("PATH"); !strings.Contains(path, action.HomeShimsDir()) && !strings.Contains(path, action.LegacyHomeGoDir()) {
 		slog.InfoContext(ctx, "Go is already removed from PATH", slog.String("SharedService", "RemoveFromPath"))
 		return nil
 	}
@@ -208,31 +972,46 @@ func (r *sharedService) RemoveFromPath(ctx context.Context, action *domain.Actio
 
 	if succeded == 0 {
 		slog.ErrorContext(ctx, "No shell rc file found", slog.String("SharedService", "RemoveFromPath"))
-		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathNoShellsFound)
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathNoShellsFound, nil)
 	}
 
 	return nil
 }
 
+// RestoreRunCommands writes back the shell rc files changed by AddToPath or
+// RemoveFromPath.
+func (r *sharedService) RestoreRunCommands(ctx context.Context, action *domain.Action) error {
+	var restoreErr error
+	for rcfPath, content := range action.RunCommands {
+		if err := r.osGateway.WriteFile(rcfPath, content, 0644); err != nil {
+			slog.ErrorContext(ctx, "Writing file", slog.String("SharedService", "RestoreRunCommands"), slog.String("file", rcfPath), slog.String("error", err.Error()))
+			restoreErr = domain.NewUnexpectedError(domain.ErrCodeRollback, err)
+		}
+	}
+	return restoreErr
+}
+
 func (r *sharedService) addToShellRunCommands(ctx context.Context, action *domain.Action, rcf string) error {
 	rcfPath := filepath.Join(action.HomeDir, rcf)
 
 	if _, err := r.osGateway.Stat(rcfPath); err != nil {
 		slog.ErrorContext(ctx, "Checking file", slog.String("SharedService", "addToShellRunCommands"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeAddToPathStat)
+		return domain.NewUnexpectedError(domain.ErrCodeAddToPathStat, err)
 	}
 
 	oldContent, err := r.osGateway.ReadFile(rcfPath)
 	if err != nil {
 		slog.ErrorContext(ctx, "Reading file", slog.String("SharedService", "addToShellRunCommands"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeAddToPathRead)
+		return domain.NewUnexpectedError(domain.ErrCodeAddToPathRead, err)
 	}
+	action.SaveRunCommand(rcfPath, oldContent)
 
-	newContent := []byte(fmt.Sprintf("%s\n%s", string(oldContent), action.Export()))
+	content := strings.ReplaceAll(string(oldContent), action.LegacyExport(), "")
+	newContent := []byte(fmt.Sprintf("%s\n%s", content, action.Export()))
 
 	if err := r.osGateway.WriteFile(rcfPath, newContent, 0644); err != nil {
 		slog.ErrorContext(ctx, "Writing file", slog.String("SharedService", "addToShellRunCommands"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeAddToPathWrite)
+		return domain.NewUnexpectedError(domain.ErrCodeAddToPathWrite, err)
 	}
 
 	return nil
@@ -243,29 +1022,37 @@ func (r *sharedService) removeFromShellRunCommands(ctx context.Context, action *
 
 	if _, err := r.osGateway.Stat(rcfPath); err != nil {
 		slog.ErrorContext(ctx, "Checking file", slog.String("SharedService", "removeFromShellRunCommands"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathStat)
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathStat, err)
 	}
 
 	oldContent, err := r.osGateway.ReadFile(rcfPath)
 	if err != nil {
 		slog.ErrorContext(ctx, "Reading file", slog.String("SharedService", "removeFromShellRunCommands"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathRead)
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathRead, err)
 	}
+	action.SaveRunCommand(rcfPath, oldContent)
 
 	newContent := strings.ReplaceAll(string(oldContent), action.Export(), "")
+	newContent = strings.ReplaceAll(newContent, action.LegacyExport(), "")
 
 	if err := r.osGateway.WriteFile(rcfPath, []byte(newContent), 0644); err != nil {
 		slog.ErrorContext(ctx, "Writing file", slog.String("SharedService", "removeFromShellRunCommands"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathWrite)
+		return domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathWrite, err)
 	}
 
 	return nil
 }
 
+// CheckInstalledVersion sets action.InstalledVersion to the version ~/.govm/current
+// points to. The go on PATH isn't asked, as the shims run the version pinned for the
+// working directory instead.
 func (r *sharedService) CheckInstalledVersion(ctx context.Context, action *domain.Action) error {
-	installedVersion, err := r.osGateway.GetInstalledGoVersion()
+	installedVersion, err := r.GetCurrentGoVersion(ctx, action)
 	if err != nil {
-		slog.ErrorContext(ctx, "Get installed version", slog.String("SharedService", "CheckInstalledVersion"), slog.String("error", err.Error()))
+		return err
+	}
+	if installedVersion == "" {
+		slog.ErrorContext(ctx, "No current version", slog.String("SharedService", "CheckInstalledVersion"))
 		return domain.NewNoGoInstallationsFoundError()
 	}
 	action.InstalledVersion = installedVersion
@@ -274,32 +1061,36 @@ func (r *sharedService) CheckInstalledVersion(ctx context.Context, action *domai
 
 func (r *sharedService) CheckAvailableUpdates(ctx context.Context, action *domain.Action) error {
 
-	availableVersions, err := r.httpGateway.GetVersions(ctx)
+	availableVersions, err := r.httpGateway.GetVersions(ctx, action.Target())
 	if err != nil {
 		slog.ErrorContext(ctx, "Get versions", slog.String("SharedService", "CheckAvailableUpdates"), slog.String("error", err.Error()))
-		return domain.NewUnexpectedError(domain.ErrCodeListVersions)
+		if errors.Is(err, gateway.ErrOffline) {
+			return domain.NewNotAvailableOfflineError(releaseIndex)
+		}
+		return domain.NewUnexpectedError(domain.ErrCodeListVersions, err)
+	}
+
+	if !action.Unstable {
+		availableVersions = availableVersions.Stable()
 	}
 
 	patch, minor, major := r.findLatestVersions(action.InstalledVersion, availableVersions.StringSlice())
 
+	latest := patch
 	switch action.UpdateStrategy {
 	case domain.MajorStrategy:
-		if major.Raw == action.InstalledVersion {
-			return domain.NewNoUpdatesAvailableError(domain.MajorStrategy, action.InstalledVersion)
-		}
-		action.Version = major.Raw
+		latest = major
 	case domain.MinorStrategy:
-		if minor.Raw == action.InstalledVersion {
-			return domain.NewNoUpdatesAvailableError(domain.MinorStrategy, action.InstalledVersion)
-		}
-		action.Version = minor.Raw
-	case domain.PatchStrategy:
-		if patch.Raw == action.InstalledVersion {
-			return domain.NewNoUpdatesAvailableError(domain.PatchStrategy, action.InstalledVersion)
-		}
-		action.Version = patch.Raw
+		latest = minor
 	}
 
+	// An unstable installed version may have no stable release of its own yet, so
+	// anything not newer than it isn't an update.
+	if latest == nil || !r.compareVersionDesc(*latest, r.parseVersion(action.InstalledVersion)) {
+		return domain.NewNoUpdatesAvailableError(action.UpdateStrategy, action.InstalledVersion)
+	}
+	action.Version = latest.Raw
+
 	return nil
 }
 
@@ -343,12 +1134,46 @@ func (r *sharedService) compareVersionDesc(v1, v2 version) bool {
 		return v1.Minor > v2.Minor
 	}
 
-	return v1.Patch > v2.Patch
+	if v1.Patch != v2.Patch {
+		return v1.Patch > v2.Patch
+	}
+
+	if v1.Pre != v2.Pre {
+		return preReleaseRank(v1.Pre) > preReleaseRank(v2.Pre)
+	}
+
+	return v1.PreNum > v2.PreNum
 }
 
+// preReleaseRank orders betas before release candidates, and both before the
+// stable release.
+func preReleaseRank(pre string) int {
+	switch pre {
+	case "beta":
+		return 0
+	case "rc":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// parseVersion parses release names such as "go1.22.3", "go1.23rc1" or
+// "go1.21beta2". A pre-release always has a zero patch, as in the release index.
 func (r *sharedService) parseVersion(s string) version {
-	parts := strings.Split(s, ".")
 	v := version{Raw: s}
+	s = strings.TrimPrefix(s, "go")
+
+	for _, pre := range []string{"beta", "rc"} {
+		if i := strings.Index(s, pre); i >= 0 {
+			v.Pre = pre
+			v.PreNum, _ = strconv.Atoi(s[i+len(pre):])
+			s = s[:i]
+			break
+		}
+	}
+
+	parts := strings.Split(s, ".")
 
 	if len(parts) > 0 {
 		v.Major, _ = strconv.Atoi(parts[0])
@@ -362,20 +1187,128 @@ func (r *sharedService) parseVersion(s string) version {
 	return v
 }
 
-func (r *sharedService) GetAvailableGoVersions(ctx context.Context) (domain.VersionsResponse, error) {
-	res, err := r.httpGateway.GetVersions(ctx)
+// GetAvailableGoVersions lists the stable versions available for the target of
+// action, and release candidates and betas too when action.Unstable is set.
+func (r *sharedService) GetAvailableGoVersions(ctx context.Context, action *domain.Action) (domain.VersionsResponse, error) {
+	res, err := r.httpGateway.GetVersions(ctx, action.Target())
 	if err != nil {
 		slog.ErrorContext(ctx, "Error while getting versions", slog.String("SharedService", "GetAvailableGoVersions"), slog.String("error", err.Error()))
-		return domain.VersionsResponse{}, domain.NewUnexpectedError(domain.ErrCodeListVersions)
+		if errors.Is(err, gateway.ErrOffline) {
+			return domain.VersionsResponse{}, domain.NewNotAvailableOfflineError(releaseIndex)
+		}
+		return domain.VersionsResponse{}, domain.NewUnexpectedError(domain.ErrCodeListVersions, err)
+	}
+	if !action.Unstable {
+		return res.Stable(), nil
 	}
 	return res, nil
 }
 
-func (r *sharedService) GetInstalledGoVersion(ctx context.Context) (string, error) {
-	res, err := r.osGateway.GetInstalledGoVersion()
+func (r *sharedService) GetLocalGoVersions(ctx context.Context, action *domain.Action) ([]string, error) {
+	entries, err := r.osGateway.ReadDir(action.HomeVersionsDir())
 	if err != nil {
-		slog.ErrorContext(ctx, "Error while getting installed version", slog.String("SharedService", "GetInstalledGoVersion"), slog.String("error", err.Error()))
-		return "", domain.NewNoGoInstallationsFoundError()
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		slog.ErrorContext(ctx, "Error while reading versions directory", slog.String("SharedService", "GetLocalGoVersions"), slog.String("error", err.Error()))
+		return nil, domain.NewUnexpectedError(domain.ErrCodeListLocalVersions, err)
 	}
-	return res, nil
+
+	versions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			versions = append(versions, e.Name())
+		}
+	}
+	return versions, nil
+}
+
+func (r *sharedService) GetCurrentGoVersion(ctx context.Context, action *domain.Action) (string, error) {
+	target, err := r.osGateway.ReadSymlink(action.HomeCurrentDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		slog.ErrorContext(ctx, "Error while reading current version link", slog.String("SharedService", "GetCurrentGoVersion"), slog.String("error", err.Error()))
+		return "", domain.NewUnexpectedError(domain.ErrCodeGetCurrentVersion, err)
+	}
+	return filepath.Base(target), nil
+}
+
+// GetCachedArchives lists the archives in the cache, most recently downloaded first.
+func (r *sharedService) GetCachedArchives(ctx context.Context, action *domain.Action) ([]domain.CachedArchive, error) {
+	entries, err := r.osGateway.ReadDir(action.CacheDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []domain.CachedArchive{}, nil
+		}
+		slog.ErrorContext(ctx, "Error while reading cache directory", slog.String("SharedService", "GetCachedArchives"), slog.String("error", err.Error()))
+		return nil, domain.NewUnexpectedError(domain.ErrCodeCacheList, err)
+	}
+
+	archives := make([]domain.CachedArchive, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		files, err := r.osGateway.ReadDir(filepath.Join(action.CacheDir(), e.Name()))
+		if err != nil {
+			slog.ErrorContext(ctx, "Error while reading cache entry", slog.String("SharedService", "GetCachedArchives"), slog.String("error", err.Error()))
+			return nil, domain.NewUnexpectedError(domain.ErrCodeCacheList, err)
+		}
+
+		for _, f := range files {
+			info, err := f.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			archives = append(archives, domain.CachedArchive{
+				Checksum: e.Name(),
+				Filename: f.Name(),
+				Size:     info.Size(),
+				ModTime:  info.ModTime(),
+			})
+		}
+	}
+
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].ModTime.After(archives[j].ModTime)
+	})
+
+	return archives, nil
+}
+
+func (r *sharedService) RemoveCachedArchive(ctx context.Context, action *domain.Action, archive domain.CachedArchive) error {
+	if err := r.osGateway.RemoveDir(filepath.Join(action.CacheDir(), archive.Checksum)); err != nil {
+		slog.ErrorContext(ctx, "Error while removing cached archive", slog.String("SharedService", "RemoveCachedArchive"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeCacheRemove, err)
+	}
+	return nil
+}
+
+func (r *sharedService) CleanCache(ctx context.Context, action *domain.Action) error {
+	if err := r.osGateway.RemoveDir(action.CacheDir()); err != nil {
+		slog.ErrorContext(ctx, "Error while removing cache directory", slog.String("SharedService", "CleanCache"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeCacheRemove, err)
+	}
+	return nil
+}
+
+// ReadConfig reads the config file as is, without defaults or environment overrides.
+func (r *sharedService) ReadConfig(ctx context.Context, action *domain.Action) (domain.Config, error) {
+	config, err := r.osGateway.ReadConfig(action.ConfigFile())
+	if err != nil {
+		slog.ErrorContext(ctx, "Error while reading config file", slog.String("SharedService", "ReadConfig"), slog.String("error", err.Error()))
+		return domain.Config{}, domain.NewUnexpectedError(domain.ErrCodeReadConfig, err)
+	}
+	return config, nil
+}
+
+func (r *sharedService) WriteConfig(ctx context.Context, action *domain.Action, config domain.Config) error {
+	if err := r.osGateway.WriteConfig(action.ConfigFile(), config); err != nil {
+		slog.ErrorContext(ctx, "Error while writing config file", slog.String("SharedService", "WriteConfig"), slog.String("error", err.Error()))
+		return domain.NewUnexpectedError(domain.ErrCodeWriteConfig, err)
+	}
+	return nil
 }
@@ -15,26 +15,110 @@ func (m *SharedServiceMock) CheckUserHome(ctx context.Context, action *domain.Ac
 	return m.Called(ctx, action).Error(0)
 }
 
+func (m *SharedServiceMock) ResolveVersion(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) ResolveActiveVersion(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
 func (m *SharedServiceMock) CheckVersion(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
 
-func (m *SharedServiceMock) DownloadVersion(ctx context.Context, action *domain.Action) error {
+func (m *SharedServiceMock) CheckLocalVersion(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
 
+func (m *SharedServiceMock) DownloadVersion(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	return m.Called(ctx, action, progress).Error(0)
+}
+
 func (m *SharedServiceMock) Checksum(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
 
-func (m *SharedServiceMock) RemoveVersion(ctx context.Context, action *domain.Action) error {
+func (m *SharedServiceMock) VerifySignature(ctx context.Context, action *domain.Action) error {
+	args := m.Called(ctx, action)
+	return args.Error(0)
+}
+
+func (m *SharedServiceMock) DownloadArchive(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	args := m.Called(ctx, action, progress)
+	return args.Error(0)
+}
+
+func (m *SharedServiceMock) CheckArchive(ctx context.Context, action *domain.Action) error {
+	args := m.Called(ctx, action)
+	return args.Error(0)
+}
+
+func (m *SharedServiceMock) CacheArchive(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) UntarFiles(ctx context.Context, action *domain.Action, progress func(written int64, total int64)) error {
+	return m.Called(ctx, action, progress).Error(0)
+}
+
+func (m *SharedServiceMock) FindBootstrap(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) FetchSource(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) BuildSource(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) InstallVersion(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) BackupVersion(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) RestoreVersion(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) RemoveVersionBackup(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) RemoveStagingDir(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) RemoveURLDownload(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) SetCurrentVersion(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) RemoveCurrentVersion(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
 
-func (m *SharedServiceMock) UntarFiles(ctx context.Context, action *domain.Action) error {
+func (m *SharedServiceMock) RestoreCurrentVersion(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
 
+func (m *SharedServiceMock) CreateShims(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) RunWithVersion(ctx context.Context, action *domain.Action, name string, args []string) (int, error) {
+	a := m.Called(ctx, action, name, args)
+	return a.Int(0), a.Error(1)
+}
+
 func (m *SharedServiceMock) AddToPath(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
@@ -43,6 +127,10 @@ func (m *SharedServiceMock) RemoveFromPath(ctx context.Context, action *domain.A
 	return m.Called(ctx, action).Error(0)
 }
 
+func (m *SharedServiceMock) RestoreRunCommands(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
 func (m *SharedServiceMock) CheckInstalledVersion(ctx context.Context, action *domain.Action) error {
 	return m.Called(ctx, action).Error(0)
 }
@@ -51,12 +139,39 @@ func (m *SharedServiceMock) CheckAvailableUpdates(ctx context.Context, action *d
 	return m.Called(ctx, action).Error(0)
 }
 
-func (m *SharedServiceMock) GetAvailableGoVersions(ctx context.Context) (domain.VersionsResponse, error) {
-	args := m.Called(ctx)
+func (m *SharedServiceMock) GetAvailableGoVersions(ctx context.Context, action *domain.Action) (domain.VersionsResponse, error) {
+	args := m.Called(ctx, action)
 	return args.Get(0).(domain.VersionsResponse), args.Error(1)
 }
 
-func (m *SharedServiceMock) GetInstalledGoVersion(ctx context.Context) (string, error) {
-	args := m.Called(ctx)
+func (m *SharedServiceMock) GetLocalGoVersions(ctx context.Context, action *domain.Action) ([]string, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *SharedServiceMock) GetCurrentGoVersion(ctx context.Context, action *domain.Action) (string, error) {
+	args := m.Called(ctx, action)
 	return args.String(0), args.Error(1)
 }
+
+func (m *SharedServiceMock) GetCachedArchives(ctx context.Context, action *domain.Action) ([]domain.CachedArchive, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).([]domain.CachedArchive), args.Error(1)
+}
+
+func (m *SharedServiceMock) RemoveCachedArchive(ctx context.Context, action *domain.Action, archive domain.CachedArchive) error {
+	return m.Called(ctx, action, archive).Error(0)
+}
+
+func (m *SharedServiceMock) CleanCache(ctx context.Context, action *domain.Action) error {
+	return m.Called(ctx, action).Error(0)
+}
+
+func (m *SharedServiceMock) ReadConfig(ctx context.Context, action *domain.Action) (domain.Config, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).(domain.Config), args.Error(1)
+}
+
+func (m *SharedServiceMock) WriteConfig(ctx context.Context, action *domain.Action, config domain.Config) error {
+	return m.Called(ctx, action, config).Error(0)
+}
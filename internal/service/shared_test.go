@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/sbonaiva/govm/internal/domain"
 	"github.com/sbonaiva/govm/internal/gateway"
@@ -17,9 +19,11 @@ import (
 )
 
 const (
-	goBinDir = "/fake/home/.govm/go/bin"
+	shimsDir = "/fake/home/.govm/shims"
 	bashDir  = "/bin/bash"
 	pathEnv  = "/usr/bin:/usr/local/bin"
+	// emptyChecksum is the sha256 of an empty file.
+	emptyChecksum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
 )
 
 var (
@@ -34,6 +38,7 @@ type sharedServiceSuite struct {
 	osGateway    *gateway.OsGatewayMock
 	httpGateway  *gateway.HttpGatewayMock
 	fileInfoMock *gateway.FileInfoMock
+	config       *domain.Config
 	sharedSvc    service.SharedService
 }
 
@@ -55,7 +60,8 @@ func (r *sharedServiceSuite) SetupTest() {
 	r.osGateway = new(gateway.OsGatewayMock)
 	r.httpGateway = new(gateway.HttpGatewayMock)
 	r.fileInfoMock = new(gateway.FileInfoMock)
-	r.sharedSvc = service.NewShared(r.httpGateway, r.osGateway)
+	r.config = &domain.Config{}
+	r.sharedSvc = service.NewShared(r.httpGateway, r.osGateway, r.config)
 }
 
 func (r *sharedServiceSuite) TestCheckUserHomeSuccess() {
@@ -66,176 +72,1246 @@ func (r *sharedServiceSuite) TestCheckUserHomeSuccess() {
 	r.NoError(err)
 }
 
+func (r *sharedServiceSuite) TestCheckUserHomeWithRoot() {
+	r.config.Root = "/opt/govm"
+	r.osGateway.On("GetUserHomeDir").Return("/home/fake", nil).Once()
+
+	err := r.sharedSvc.CheckUserHome(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal("/home/fake", r.action.HomeDir)
+	r.Equal("/opt/govm/shims", r.action.HomeShimsDir())
+}
+
 func (r *sharedServiceSuite) TestCheckUserHomeError() {
 	r.osGateway.On("GetUserHomeDir").Return("", errors.New("error")).Once()
 
-	err := r.sharedSvc.CheckUserHome(r.ctx, r.action)
+	err := r.sharedSvc.CheckUserHome(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCheckUserHome, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestResolveVersionAlreadySet() {
+	err := r.sharedSvc.ResolveVersion(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal("1.19.3", r.action.Version)
+}
+
+func (r *sharedServiceSuite) TestResolveVersionSuccess() {
+	tests := []struct {
+		name            string
+		files           map[string]string
+		expectedVersion string
+	}{
+		{
+			name:            "go-version file",
+			files:           map[string]string{"/home/fake/project/.go-version": "# pinned\n1.22.3\n"},
+			expectedVersion: "go1.22.3",
+		},
+		{
+			name:            "go-version file with prefix",
+			files:           map[string]string{"/home/fake/project/.go-version": "go1.20.14"},
+			expectedVersion: "go1.20.14",
+		},
+		{
+			name:            "go.mod toolchain directive",
+			files:           map[string]string{"/home/fake/project/go.mod": "module example.com/fake\n\ngo 1.22 // language version\n\ntoolchain go1.22.5\n"},
+			expectedVersion: "go1.22.5",
+		},
+		{
+			name:            "go.mod go directive",
+			files:           map[string]string{"/home/fake/project/go.mod": "module example.com/fake\n\ngo 1.23\n"},
+			expectedVersion: "go1.23.0",
+		},
+		{
+			name:            "go.mod go directive before go 1.21",
+			files:           map[string]string{"/home/fake/project/go.mod": "module example.com/fake\n\ngo 1.20\n"},
+			expectedVersion: "go1.20",
+		},
+		{
+			name: "go-version file takes precedence over go.mod",
+			files: map[string]string{
+				"/home/fake/project/.go-version": "1.21.13",
+				"/home/fake/project/go.mod":      "module example.com/fake\n\ngo 1.23.1\n",
+			},
+			expectedVersion: "go1.21.13",
+		},
+		{
+			name:            "parent directory",
+			files:           map[string]string{"/home/fake/.go-version": "1.22.3"},
+			expectedVersion: "go1.22.3",
+		},
+	}
+
+	for _, tc := range tests {
+		r.Run(tc.name, func() {
+			osGateway := new(gateway.OsGatewayMock)
+			sharedSvc := service.NewShared(r.httpGateway, osGateway, r.config)
+			action := &domain.Action{}
+
+			osGateway.On("GetWorkingDir").Return("/home/fake/project", nil).Once()
+			for path, content := range tc.files {
+				osGateway.On("ReadFile", path).Return([]byte(content), nil)
+			}
+			osGateway.On("ReadFile", mock.AnythingOfType("string")).Return([]byte{}, os.ErrNotExist)
+
+			err := sharedSvc.ResolveVersion(r.ctx, action)
+
+			r.NoError(err)
+			r.Equal(tc.expectedVersion, action.Version)
+		})
+	}
+}
+
+func (r *sharedServiceSuite) TestResolveVersionNotFoundError() {
+	action := &domain.Action{}
+
+	r.osGateway.On("GetWorkingDir").Return("/home/fake/project", nil).Once()
+	r.osGateway.On("ReadFile", mock.AnythingOfType("string")).Return([]byte{}, os.ErrNotExist).Times(8)
+
+	err := r.sharedSvc.ResolveVersion(r.ctx, action)
+
+	r.Error(err)
+	r.Equal(domain.NewNoPinnedVersionFoundError("/home/fake/project"), err)
+	r.Empty(action.Version)
+}
+
+func (r *sharedServiceSuite) TestResolveVersionWorkingDirError() {
+	action := &domain.Action{}
+
+	r.osGateway.On("GetWorkingDir").Return("", errors.New("error")).Once()
+
+	err := r.sharedSvc.ResolveVersion(r.ctx, action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeResolveWorkingDir, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestResolveVersionReadFileError() {
+	action := &domain.Action{}
+
+	r.osGateway.On("GetWorkingDir").Return("/home/fake/project", nil).Once()
+	r.osGateway.On("ReadFile", "/home/fake/project/.go-version").Return([]byte{}, errors.New("error")).Once()
+
+	err := r.sharedSvc.ResolveVersion(r.ctx, action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeResolveReadFile, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestResolveActiveVersionSuccess() {
+	tests := []struct {
+		name            string
+		env             string
+		files           map[string]string
+		current         string
+		expectedVersion string
+		expectedError   error
+	}{
+		{
+			name:            "environment variable",
+			env:             "1.21.5",
+			expectedVersion: "go1.21.5",
+		},
+		{
+			name:            "pinned version",
+			files:           map[string]string{"/home/fake/project/go.mod": "module example.com/fake\n\ngo 1.22.3\n"},
+			expectedVersion: "go1.22.3",
+		},
+		{
+			name:            "current version",
+			current:         "/home/fake/.govm/versions/go1.20.14",
+			expectedVersion: "go1.20.14",
+		},
+		{
+			name:          "no active version",
+			expectedError: domain.NewNoActiveVersionError(),
+		},
+	}
+
+	for _, tc := range tests {
+		r.Run(tc.name, func() {
+			osGateway := new(gateway.OsGatewayMock)
+			sharedSvc := service.NewShared(r.httpGateway, osGateway, r.config)
+			action := &domain.Action{HomeDir: "/home/fake"}
+
+			osGateway.On("GetEnv", "GOVM_VERSION").Return(tc.env)
+			osGateway.On("GetWorkingDir").Return("/home/fake/project", nil)
+			for path, content := range tc.files {
+				osGateway.On("ReadFile", path).Return([]byte(content), nil)
+			}
+			osGateway.On("ReadFile", mock.AnythingOfType("string")).Return([]byte{}, os.ErrNotExist)
+			if tc.current != "" {
+				osGateway.On("ReadSymlink", action.HomeCurrentDir()).Return(tc.current, nil)
+			} else {
+				osGateway.On("ReadSymlink", action.HomeCurrentDir()).Return("", os.ErrNotExist)
+			}
+
+			err := sharedSvc.ResolveActiveVersion(r.ctx, action)
+
+			r.Equal(tc.expectedError, err)
+			r.Equal(tc.expectedVersion, action.Version)
+		})
+	}
+}
+
+func (r *sharedServiceSuite) TestResolveActiveVersionAlreadySet() {
+	err := r.sharedSvc.ResolveActiveVersion(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal("1.19.3", r.action.Version)
+}
+
+func (r *sharedServiceSuite) TestCheckVersionSuccess() {
+	r.httpGateway.On("VersionExists", r.ctx, r.action.Version, domain.HostPlatform()).Return(true, nil).Once()
+
+	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestCheckVersionError() {
+	r.httpGateway.On("VersionExists", r.ctx, r.action.Version, domain.HostPlatform()).Return(false, errors.New("error")).Once()
+
+	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCheckVersion, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestCheckVersionOfflineError() {
+	r.httpGateway.On("VersionExists", r.ctx, r.action.Version, domain.HostPlatform()).Return(false, gateway.ErrOffline).Once()
+
+	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewNotAvailableOfflineError("the Go release index"), err)
+}
+
+func (r *sharedServiceSuite) TestCheckVersionNotExistsError() {
+	r.httpGateway.On("VersionExists", r.ctx, r.action.Version, domain.HostPlatform()).Return(false, nil).Once()
+
+	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewVersionNotAvailableError(r.action.Version), err)
+}
+
+func (r *sharedServiceSuite) TestCheckVersionNotAvailableForTarget() {
+	r.action.OS = "plan9"
+	r.action.Arch = "arm"
+	r.httpGateway.On("VersionExists", r.ctx, r.action.Version, domain.Platform{OS: "plan9", Arch: "arm"}).Return(false, nil).Once()
+
+	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+
+	r.Equal(domain.NewVersionNotAvailableForError(r.action.Version, domain.Platform{OS: "plan9", Arch: "arm"}), err)
+}
+
+func (r *sharedServiceSuite) TestCheckVersionUnstable() {
+	tests := []struct {
+		version       string
+		unstable      bool
+		expectedError error
+	}{
+		{version: "go1.23rc1", expectedError: domain.NewUnstableVersionError("go1.23rc1")},
+		{version: "go1.23beta2", expectedError: domain.NewUnstableVersionError("go1.23beta2")},
+		{version: "go1.23rc1", unstable: true},
+		{version: "go1.22.5"},
+	}
+
+	for _, tc := range tests {
+		r.Run(tc.version, func() {
+			action := &domain.Action{Version: tc.version, Unstable: tc.unstable}
+			r.httpGateway.On("VersionExists", r.ctx, tc.version, domain.HostPlatform()).Return(true, nil).Once()
+
+			err := r.sharedSvc.CheckVersion(r.ctx, action)
+
+			r.Equal(tc.expectedError, err)
+		})
+	}
+}
+
+func (r *sharedServiceSuite) TestCheckLocalVersionSuccess() {
+	r.osGateway.On("Stat", r.action.HomeVersionDir()).Return(r.fileInfoMock, nil).Once()
+
+	err := r.sharedSvc.CheckLocalVersion(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestCheckLocalVersionNotInstalledError() {
+	r.osGateway.On("Stat", r.action.HomeVersionDir()).Return(r.fileInfoMock, os.ErrNotExist).Once()
+
+	err := r.sharedSvc.CheckLocalVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewVersionNotInstalledError(r.action.Version), err)
+}
+
+func (r *sharedServiceSuite) TestCheckLocalVersionError() {
+	r.osGateway.On("Stat", r.action.HomeVersionDir()).Return(r.fileInfoMock, errors.New("error")).Once()
+
+	err := r.sharedSvc.CheckLocalVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCheckLocalVersion, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionSuccess() {
+	var osNilFile *os.File
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.Checksum = "checksum"
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("AppendFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile, mock.Anything).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.False(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionCached() {
+	cachedFile, _ := os.CreateTemp("", "")
+	hash := sha256.New()
+	checksum := fmt.Sprintf("%x", hash.Sum(nil))
+	r.action.Checksum = checksum
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return(checksum, nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(cachedFile, nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.True(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionCachedChecksumMismatch() {
+	cachedFile, _ := os.CreateTemp("", "")
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.Checksum = "checksum"
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(cachedFile, nil).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("AppendFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile, mock.Anything).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.False(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionGetChecksumError() {
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("", errors.New("error")).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumDownload, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionCreateCacheDirError() {
+	var osNilFile *os.File
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", mock.AnythingOfType("string")).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadCreateDir, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionCreateDirError() {
+	var osNilFile *os.File
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", mock.AnythingOfType("string")).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("AppendFile", r.action.DownloadFile()).Return(osNilFile, errors.New("error")).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadCreateFile, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionError() {
+	var osNilFile *os.File
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.Checksum = "checksum"
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("AppendFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile, mock.Anything).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionOfflineError() {
+	var osNilFile *os.File
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.Checksum = "checksum"
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("AppendFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile, mock.Anything).Return(gateway.ErrOffline).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewNotAvailableOfflineError("the archive of go version \"1.19.3\""), err)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionStreamed() {
+	var osNilFile *os.File
+	r.config.Cache = domain.OffCachePolicy
+	checksum := fmt.Sprintf("%x", sha256.Sum256([]byte("archive")))
+	r.action.Checksum = checksum
+	stream, untar := r.streamed("archive", true)
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return(checksum, nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.httpGateway.On("StreamVersion", r.ctx, r.action, mock.Anything, mock.Anything).Run(stream).Return(nil).Once()
+	r.osGateway.On("UntarReader", r.ctx, mock.Anything, r.action.HomeVersionStagingDir()).Run(untar).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.True(r.action.Extracted)
+	r.False(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionStreamedChecksumMismatch() {
+	var osNilFile *os.File
+	r.action.Checksum = "checksum"
+	r.config.Cache = domain.OffCachePolicy
+	stream, untar := r.streamed("", true)
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.httpGateway.On("StreamVersion", r.ctx, r.action, mock.Anything, mock.Anything).Run(stream).Return(nil).Once()
+	r.osGateway.On("UntarReader", r.ctx, mock.Anything, r.action.HomeVersionStagingDir()).Run(untar).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, fmt.Errorf("expected checksum checksum, got %s", emptyChecksum)), err)
+	r.False(r.action.Extracted)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionStreamedExtractError() {
+	var osNilFile *os.File
+	r.action.Checksum = "checksum"
+	r.config.Cache = domain.OffCachePolicy
+	stream, untar := r.streamed("archive", false)
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.httpGateway.On("StreamVersion", r.ctx, r.action, mock.Anything, mock.Anything).Run(stream).Return(io.ErrClosedPipe).Once()
+	r.osGateway.On("UntarReader", r.ctx, mock.Anything, r.action.HomeVersionStagingDir()).Run(untar).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeUntarExtract, errors.New("error")), err)
+	r.False(r.action.Extracted)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionStreamedDownloadError() {
+	var osNilFile *os.File
+	r.action.Checksum = "checksum"
+	r.config.Cache = domain.OffCachePolicy
+	stream, untar := r.streamed("", true)
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.httpGateway.On("StreamVersion", r.ctx, r.action, mock.Anything, mock.Anything).Run(stream).Return(errors.New("error")).Once()
+	r.osGateway.On("UntarReader", r.ctx, mock.Anything, r.action.HomeVersionStagingDir()).Run(untar).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, errors.New("error")), err)
+	r.False(r.action.Extracted)
+}
+
+func (r *sharedServiceSuite) TestDownloadVersionNotStreamedWithSignature() {
+	var osNilFile *os.File
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.Checksum = "checksum"
+	r.config.Cache = domain.OffCachePolicy
+	r.config.Signature = domain.WarnSignaturePolicy
+
+	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version, domain.HostPlatform()).Return("checksum", nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(osNilFile, os.ErrNotExist).Once()
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("AppendFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile, mock.Anything).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadVersion(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.False(r.action.Extracted)
+}
+
+func (r *sharedServiceSuite) TestChecksumSuccess() {
+	checksumFile, _ := os.CreateTemp("", "")
+	hash := sha256.New()
+	r.action.Checksum = fmt.Sprintf("%x", hash.Sum(nil))
+
+	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(checksumFile, nil).Once()
+
+	err := r.sharedSvc.Checksum(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestChecksumCached() {
+	r.action.Cached = true
+
+	err := r.sharedSvc.Checksum(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestChecksumExtracted() {
+	r.action.Extracted = true
+
+	err := r.sharedSvc.Checksum(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestChecksumOpenFileError() {
+	var osNilFile *os.File
+
+	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(osNilFile, errors.New("error")).Once()
+
+	err := r.sharedSvc.Checksum(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumOpenFile, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestChecksumMismatchError() {
+	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(os.CreateTemp("", "")).Once()
+	r.osGateway.On("RemoveFile", r.action.DownloadFile()).Return(nil).Once()
+
+	err := r.sharedSvc.Checksum(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, fmt.Errorf("expected checksum %s, got %s", r.action.Checksum, emptyChecksum)), err)
+}
+
+func (r *sharedServiceSuite) TestVerifySignatureOff() {
+	err := r.sharedSvc.VerifySignature(r.ctx, r.action)
+
+	r.NoError(err)
+	r.False(r.action.SignatureUnverified)
+}
+
+func (r *sharedServiceSuite) TestVerifySignatureCached() {
+	r.config.Signature = domain.RequireSignaturePolicy
+	r.action.Cached = true
+
+	r.httpGateway.On("GetSignature", r.ctx, r.action).Return([]byte("xpto"), nil).Once()
+	r.osGateway.On("OpenFile", r.action.CacheFile()).Return(os.CreateTemp("", "")).Once()
+
+	err := r.sharedSvc.VerifySignature(r.ctx, r.action)
+
+	r.ErrorIs(err, domain.ErrChecksum)
+	r.ErrorContains(err, fmt.Sprintf("the signature of \"%s\" could not be verified: ", r.action.Filename()))
+}
+
+func (r *sharedServiceSuite) TestVerifySignatureWarn() {
+	r.config.Signature = domain.WarnSignaturePolicy
+
+	r.httpGateway.On("GetSignature", r.ctx, r.action).Return([]byte(nil), errors.New("error")).Once()
+
+	err := r.sharedSvc.VerifySignature(r.ctx, r.action)
+
+	r.NoError(err)
+	r.True(r.action.SignatureUnverified)
+}
+
+func (r *sharedServiceSuite) TestVerifySignatureRequireDownloadError() {
+	r.config.Signature = domain.RequireSignaturePolicy
+
+	r.httpGateway.On("GetSignature", r.ctx, r.action).Return([]byte(nil), errors.New("error")).Once()
+
+	err := r.sharedSvc.VerifySignature(r.ctx, r.action)
+
+	r.Equal(domain.NewSignatureNotVerifiedError(r.action.Filename(), errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestVerifySignatureRequireInvalidSignature() {
+	r.config.Signature = domain.RequireSignaturePolicy
+
+	r.httpGateway.On("GetSignature", r.ctx, r.action).Return([]byte("xpto"), nil).Once()
+	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(os.CreateTemp("", "")).Once()
+
+	err := r.sharedSvc.VerifySignature(r.ctx, r.action)
+
+	r.ErrorIs(err, domain.ErrChecksum)
+	r.ErrorContains(err, fmt.Sprintf("the signature of \"%s\" could not be verified: ", r.action.Filename()))
+	r.False(r.action.SignatureUnverified)
+}
+
+func (r *sharedServiceSuite) TestCacheArchiveSuccess() {
+	r.action.Checksum = "checksum"
+
+	r.osGateway.On("CreateDir", r.action.CacheArchiveDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.DownloadFile(), r.action.CacheFile()).Return(nil).Once()
+
+	err := r.sharedSvc.CacheArchive(r.ctx, r.action)
+
+	r.NoError(err)
+	r.True(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestCacheArchiveAlreadyCached() {
+	r.action.Cached = true
+
+	err := r.sharedSvc.CacheArchive(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestCacheArchiveExtracted() {
+	r.action.Extracted = true
+
+	err := r.sharedSvc.CacheArchive(r.ctx, r.action)
+
+	r.NoError(err)
+	r.False(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestCacheArchiveCreateDirError() {
+	r.osGateway.On("CreateDir", r.action.CacheArchiveDir(), fileModeType).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.CacheArchive(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCacheCreateDir, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestCacheArchiveRenameError() {
+	r.osGateway.On("CreateDir", r.action.CacheArchiveDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.DownloadFile(), r.action.CacheFile()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.CacheArchive(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCacheStore, errors.New("error")), err)
+	r.False(r.action.Cached)
+}
+
+func (r *sharedServiceSuite) TestDownloadArchiveWithoutURL() {
+	err := r.sharedSvc.DownloadArchive(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.Empty(r.action.Archive)
+}
+
+func (r *sharedServiceSuite) TestDownloadArchiveSuccess() {
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.ArchiveURL = "https://example.com/go1.22.3.linux-amd64.tar.gz"
+
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("CreateFile", r.action.URLDownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadURL", r.ctx, r.action.ArchiveURL, tempFile, mock.Anything).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadArchive(r.ctx, r.action, nil)
+
+	r.NoError(err)
+	r.Equal(r.action.URLDownloadFile(), r.action.Archive)
+}
+
+func (r *sharedServiceSuite) TestDownloadArchiveError() {
+	tempFile, _ := os.CreateTemp("", "")
+	r.action.ArchiveURL = "https://example.com/go1.22.3.linux-amd64.tar.gz"
+
+	r.osGateway.On("CreateDir", r.action.CacheDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("CreateFile", r.action.URLDownloadFile()).Return(tempFile, nil).Once()
+	r.httpGateway.On("DownloadURL", r.ctx, r.action.ArchiveURL, tempFile, mock.Anything).Return(errors.New("error")).Once()
+	r.osGateway.On("RemoveFile", r.action.URLDownloadFile()).Return(nil).Once()
+
+	err := r.sharedSvc.DownloadArchive(r.ctx, r.action, nil)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadVersion, errors.New("error")), err)
+	r.Empty(r.action.Archive)
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveSuccess() {
+	r.action.Version = ""
+	r.action.Archive = "/tmp/go1.22.3.linux-amd64.tar.gz"
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte("go1.22.3\ntime 2024-04-30T19:26:11Z\n"), nil).Once()
+	r.osGateway.On("ReadArchivePlatform", r.action.Archive).Return(domain.HostPlatform(), nil).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal("go1.22.3", r.action.Version)
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveWithChecksum() {
+	archive, _ := os.CreateTemp("", "")
+	r.action.Archive = archive.Name()
+	r.action.Checksum = fmt.Sprintf("%x", sha256.New().Sum(nil))
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte("go1.22.3"), nil).Once()
+	r.osGateway.On("ReadArchivePlatform", r.action.Archive).Return(domain.HostPlatform(), nil).Once()
+	r.osGateway.On("OpenFile", r.action.Archive).Return(archive, nil).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveChecksumMismatch() {
+	archive, _ := os.CreateTemp("", "")
+	r.action.ArchiveURL = "https://example.com/go1.22.3.linux-amd64.tar.gz"
+	r.action.Archive = r.action.URLDownloadFile()
+	r.action.Checksum = "checksum"
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte("go1.22.3"), nil).Once()
+	r.osGateway.On("ReadArchivePlatform", r.action.Archive).Return(domain.HostPlatform(), nil).Once()
+	r.osGateway.On("OpenFile", r.action.Archive).Return(archive, nil).Once()
+	r.osGateway.On("RemoveFile", r.action.URLDownloadFile()).Return(nil).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch, fmt.Errorf("expected checksum checksum, got %s", emptyChecksum)), err)
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveWithoutVersion() {
+	r.action.Archive = "/tmp/archive.tar.gz"
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte(nil), os.ErrNotExist).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.Equal(domain.NewInvalidArchiveError(r.action.Archive, os.ErrNotExist), err)
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveInvalidVersion() {
+	r.action.Archive = "/tmp/archive.tar.gz"
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte("../../go1.22.3"), nil).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.Equal(domain.NewInvalidArchiveError(r.action.Archive, errors.New(`unexpected version "../../go1.22.3" in VERSION file`)), err)
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveOtherPlatform() {
+	r.action.ArchiveURL = "https://example.com/go1.22.3.plan9-arm.tar.gz"
+	r.action.Archive = r.action.URLDownloadFile()
+	platform := domain.Platform{OS: "plan9", Arch: "arm"}
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte("go1.22.3"), nil).Once()
+	r.osGateway.On("ReadArchivePlatform", r.action.Archive).Return(platform, nil).Once()
+	r.osGateway.On("RemoveFile", r.action.URLDownloadFile()).Return(nil).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.Equal(domain.NewArchivePlatformError(r.action.ArchiveURL, platform, domain.HostPlatform()), err)
+	r.Equal(domain.ExitFailure, domain.ExitCode(err))
+}
+
+func (r *sharedServiceSuite) TestCheckArchiveWithoutPlatform() {
+	r.action.Archive = "/tmp/archive.tar.gz"
+
+	r.osGateway.On("ReadArchiveFile", r.action.Archive, "VERSION").Return([]byte("go1.22.3"), nil).Once()
+	r.osGateway.On("ReadArchivePlatform", r.action.Archive).Return(domain.Platform{}, os.ErrNotExist).Once()
+
+	err := r.sharedSvc.CheckArchive(r.ctx, r.action)
+
+	r.Equal(domain.NewInvalidArchiveError(r.action.Archive, os.ErrNotExist), err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesFromArchive() {
+	r.config.Cache = domain.OffCachePolicy
+	r.action.ArchiveURL = "https://example.com/go1.22.3.linux-amd64.tar.gz"
+	r.action.Archive = r.action.URLDownloadFile()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Untar", r.ctx, r.action.Archive, r.action.HomeVersionStagingDir(), mock.Anything).Return(nil).Once()
+	r.osGateway.On("RemoveFile", r.action.URLDownloadFile()).Return(nil).Once()
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesSuccess() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Untar", r.ctx, r.action.CacheFile(), r.action.HomeVersionStagingDir(), mock.Anything).Return(nil).Once()
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesExtracted() {
+	r.action.Extracted = true
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesWithCacheOff() {
+	r.config.Cache = domain.OffCachePolicy
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Untar", r.ctx, r.action.CacheFile(), r.action.HomeVersionStagingDir(), mock.Anything).Return(nil).Once()
+	r.osGateway.On("RemoveDir", r.action.CacheArchiveDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesRemoveStagingError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeUntarCreateDir, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesCreateDirError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+	r.osGateway.On("CreateDir", mock.AnythingOfType("string"), fileModeType).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeUntarCreateDir, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesExtractError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Untar", r.ctx, r.action.CacheFile(), r.action.HomeVersionStagingDir(), mock.Anything).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.UntarFiles(r.ctx, r.action, nil)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeUntarExtract, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestUntarFilesCancelled() {
+	ctx, cancel := context.WithCancel(r.ctx)
+	cancel()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("CreateDir", r.action.HomeVersionStagingDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("Untar", ctx, r.action.CacheFile(), r.action.HomeVersionStagingDir(), mock.Anything).Return(context.Canceled).Once()
+
+	err := r.sharedSvc.UntarFiles(ctx, r.action, nil)
+
+	r.Equal(domain.NewCancelledError(), err)
+}
+
+func (r *sharedServiceSuite) TestFindBootstrapCurrentVersion() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("/home/fake/.govm/versions/go1.22.3", nil).Once()
+
+	err := r.sharedSvc.FindBootstrap(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal(filepath.Join(r.action.HomeVersionsDir(), "go1.22.3"), r.action.Bootstrap)
+}
+
+func (r *sharedServiceSuite) TestFindBootstrapNewestVersion() {
+	dir := r.T().TempDir()
+	for _, v := range []string{"go1.21.13", "go1.23.1", "gotip", "go1.22.8"} {
+		os.Mkdir(filepath.Join(dir, v), 0755)
+	}
+	entries, _ := os.ReadDir(dir)
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", os.ErrNotExist).Once()
+	r.osGateway.On("ReadDir", r.action.HomeVersionsDir()).Return(entries, nil).Once()
+
+	err := r.sharedSvc.FindBootstrap(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal(filepath.Join(r.action.HomeVersionsDir(), "go1.23.1"), r.action.Bootstrap)
+}
+
+func (r *sharedServiceSuite) TestFindBootstrapNoVersions() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", os.ErrNotExist).Once()
+	r.osGateway.On("ReadDir", r.action.HomeVersionsDir()).Return([]os.DirEntry{}, os.ErrNotExist).Once()
+
+	err := r.sharedSvc.FindBootstrap(r.ctx, r.action)
+
+	r.Equal(domain.NewNoBootstrapVersionError(), err)
+	r.Empty(r.action.Bootstrap)
+}
+
+func (r *sharedServiceSuite) TestFindBootstrapError() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", errors.New("error")).Once()
+
+	err := r.sharedSvc.FindBootstrap(r.ctx, r.action)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeGetCurrentVersion, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestFetchSourceSuccess() {
+	r.action.Source = "master"
+	r.action.SourceRepo = domain.GoSourceRepo
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+	r.osGateway.On("FetchSource", r.ctx, domain.GoSourceRepo, "master", r.action.HomeVersionStagingDir()).Return([]byte{}, nil).Once()
+
+	err := r.sharedSvc.FetchSource(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestFetchSourceRemoveStagingError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.FetchSource(r.ctx, r.action)
+
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeSourceCreateDir, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestFetchSourceError() {
+	r.action.Source = "xpto"
+	r.action.SourceRepo = domain.GoSourceRepo
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("FetchSource", r.ctx, domain.GoSourceRepo, "xpto", r.action.HomeVersionStagingDir()).Return([]byte("fatal: couldn't find remote ref xpto"), errors.New("error")).Once()
+
+	err := r.sharedSvc.FetchSource(r.ctx, r.action)
+
+	r.Equal(domain.NewSourceFetchError("xpto", domain.GoSourceRepo, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestFetchSourceCancelled() {
+	ctx, cancel := context.WithCancel(r.ctx)
+	cancel()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Twice()
+	r.osGateway.On("FetchSource", ctx, "", "", r.action.HomeVersionStagingDir()).Return([]byte{}, context.Canceled).Once()
+
+	err := r.sharedSvc.FetchSource(ctx, r.action)
+
+	r.Equal(domain.NewCancelledError(), err)
+}
+
+func (r *sharedServiceSuite) TestBuildSourceSuccess() {
+	r.action.Bootstrap = "/home/fake/.govm/versions/go1.22.3"
+	r.osGateway.On("BuildSource", r.ctx, r.action.HomeVersionStagingDir(), r.action.Bootstrap).Return([]byte("ALL DONE"), nil).Once()
+
+	err := r.sharedSvc.BuildSource(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestBuildSourceError() {
+	r.action.Source = "master"
+	r.action.Bootstrap = "/home/fake/.govm/versions/go1.22.3"
+	r.osGateway.On("BuildSource", r.ctx, r.action.HomeVersionStagingDir(), r.action.Bootstrap).Return([]byte("FAIL"), errors.New("exit status 2")).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+
+	err := r.sharedSvc.BuildSource(r.ctx, r.action)
+
+	r.Equal(domain.NewSourceBuildError("master", errors.New("exit status 2")), err)
+}
+
+func (r *sharedServiceSuite) TestBuildSourceCancelled() {
+	ctx, cancel := context.WithCancel(r.ctx)
+	cancel()
+	r.osGateway.On("BuildSource", ctx, r.action.HomeVersionStagingDir(), "").Return([]byte{}, context.Canceled).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
+
+	err := r.sharedSvc.BuildSource(ctx, r.action)
+
+	r.Equal(domain.NewCancelledError(), err)
+}
+
+func (r *sharedServiceSuite) TestInstallVersionSuccess() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionBackupDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionDir(), r.action.HomeVersionBackupDir()).Return(os.ErrNotExist).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionStagingDir(), r.action.HomeVersionDir()).Return(nil).Once()
+
+	err := r.sharedSvc.InstallVersion(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestInstallVersionBackupError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionBackupDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionDir(), r.action.HomeVersionBackupDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.InstallVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCheckUserHome), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveVersion, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestCheckVersionSuccess() {
-	r.httpGateway.On("VersionExists", r.ctx, r.action.Version).Return(true, nil).Once()
+func (r *sharedServiceSuite) TestInstallVersionRenameError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionBackupDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionDir(), r.action.HomeVersionBackupDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionStagingDir(), r.action.HomeVersionDir()).Return(errors.New("error")).Once()
+	r.osGateway.On("RemoveDir", r.action.HomeVersionDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionBackupDir(), r.action.HomeVersionDir()).Return(nil).Once()
 
-	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+	err := r.sharedSvc.InstallVersion(r.ctx, r.action)
 
-	r.NoError(err)
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeInstallVersion, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestCheckVersionError() {
-	r.httpGateway.On("VersionExists", r.ctx, r.action.Version).Return(false, errors.New("error")).Once()
+func (r *sharedServiceSuite) TestBackupVersionSuccess() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionBackupDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionDir(), r.action.HomeVersionBackupDir()).Return(nil).Once()
 
-	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+	err := r.sharedSvc.BackupVersion(r.ctx, r.action)
 
-	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCheckVersion), err)
+	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestCheckVersionNotExistsError() {
-	r.httpGateway.On("VersionExists", r.ctx, r.action.Version).Return(false, nil).Once()
+func (r *sharedServiceSuite) TestBackupVersionRemoveDirError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionBackupDir()).Return(errors.New("error")).Once()
 
-	err := r.sharedSvc.CheckVersion(r.ctx, r.action)
+	err := r.sharedSvc.BackupVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewVersionNotAvailableError(r.action.Version), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveVersion, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestDownloadVersionSuccess() {
-	tempFile, _ := os.CreateTemp("", "")
+func (r *sharedServiceSuite) TestRestoreVersionSuccess() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionBackupDir(), r.action.HomeVersionDir()).Return(nil).Once()
+
+	err := r.sharedSvc.RestoreVersion(r.ctx, r.action)
+
+	r.NoError(err)
+}
 
-	r.osGateway.On("RemoveDir", r.action.DownloadFile()).Return(nil).Once()
-	r.osGateway.On("CreateFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
-	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile).Return(nil).Once()
+func (r *sharedServiceSuite) TestRestoreVersionWithoutBackup() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionBackupDir(), r.action.HomeVersionDir()).Return(os.ErrNotExist).Once()
 
-	err := r.sharedSvc.DownloadVersion(r.ctx, r.action)
+	err := r.sharedSvc.RestoreVersion(r.ctx, r.action)
 
 	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestDownloadVersionRemoveDirError() {
-	r.osGateway.On("RemoveDir", r.action.DownloadFile()).Return(errors.New("error")).Once()
+func (r *sharedServiceSuite) TestRestoreVersionRemoveDirError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionDir()).Return(errors.New("error")).Once()
 
-	err := r.sharedSvc.DownloadVersion(r.ctx, r.action)
+	err := r.sharedSvc.RestoreVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadRemoveDir), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRollback, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestDownloadVersionCreateDirError() {
-	var osNilFile *os.File
-
-	r.osGateway.On("RemoveDir", r.action.DownloadFile()).Return(nil).Once()
-	r.osGateway.On("CreateFile", r.action.DownloadFile()).Return(osNilFile, errors.New("error")).Once()
+func (r *sharedServiceSuite) TestRestoreVersionRenameError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionDir()).Return(nil).Once()
+	r.osGateway.On("Rename", r.action.HomeVersionBackupDir(), r.action.HomeVersionDir()).Return(errors.New("error")).Once()
 
-	err := r.sharedSvc.DownloadVersion(r.ctx, r.action)
+	err := r.sharedSvc.RestoreVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadCreateFile), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRollback, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestDownloadVersionError() {
-	tempFile, _ := os.CreateTemp("", "")
+func (r *sharedServiceSuite) TestRemoveVersionBackup() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionBackupDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.RemoveVersionBackup(r.ctx, r.action)
+
+	r.NoError(err)
+}
 
-	r.osGateway.On("RemoveDir", r.action.DownloadFile()).Return(nil).Once()
-	r.osGateway.On("CreateFile", r.action.DownloadFile()).Return(tempFile, nil).Once()
-	r.httpGateway.On("DownloadVersion", r.ctx, r.action, tempFile).Return(errors.New("error")).Once()
+func (r *sharedServiceSuite) TestRemoveStagingDirSuccess() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(nil).Once()
 
-	err := r.sharedSvc.DownloadVersion(r.ctx, r.action)
+	err := r.sharedSvc.RemoveStagingDir(r.ctx, r.action)
 
-	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeDownloadVersion), err)
+	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestChecksumSuccess() {
-	downloadFile, _ := os.CreateTemp("", "")
-	checksumFile, _ := os.CreateTemp("", "")
-	hash := sha256.New()
-	io.Copy(hash, downloadFile)
+func (r *sharedServiceSuite) TestRemoveStagingDirError() {
+	r.osGateway.On("RemoveDir", r.action.HomeVersionStagingDir()).Return(errors.New("error")).Once()
 
-	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version).Return(fmt.Sprintf("%x", hash.Sum(nil)), nil).Once()
-	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(checksumFile, nil).Once()
+	err := r.sharedSvc.RemoveStagingDir(r.ctx, r.action)
 
-	err := r.sharedSvc.Checksum(r.ctx, r.action)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRollback, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestRemoveURLDownload() {
+	r.action.ArchiveURL = "https://example.com/go1.22.3.linux-amd64.tar.gz"
+	r.osGateway.On("RemoveFile", r.action.URLDownloadFile()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.RemoveURLDownload(r.ctx, r.action)
 
 	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestChecksumDownloadError() {
-	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version).Return("", errors.New("error")).Once()
+func (r *sharedServiceSuite) TestRemoveURLDownloadWithoutURL() {
+	err := r.sharedSvc.RemoveURLDownload(r.ctx, r.action)
 
-	err := r.sharedSvc.Checksum(r.ctx, r.action)
+	r.NoError(err)
+	r.osGateway.AssertNotCalled(r.T(), "RemoveFile", mock.Anything)
+}
+
+func (r *sharedServiceSuite) TestSetCurrentVersionSuccess() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("/home/fake/.govm/versions/go1.19.2", nil).Once()
+	r.osGateway.On("CreateSymlink", r.action.HomeVersionDir(), r.action.HomeCurrentDir()).Return(nil).Once()
+
+	err := r.sharedSvc.SetCurrentVersion(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal("/home/fake/.govm/versions/go1.19.2", r.action.PreviousCurrent)
+}
+
+func (r *sharedServiceSuite) TestSetCurrentVersionError() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", os.ErrNotExist).Once()
+	r.osGateway.On("CreateSymlink", r.action.HomeVersionDir(), r.action.HomeCurrentDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.SetCurrentVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumDownload), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeSetCurrentVersion, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestChecksumOpenFileError() {
-	var osNilFile *os.File
+func (r *sharedServiceSuite) TestRemoveCurrentVersionSuccess() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", os.ErrNotExist).Once()
+	r.osGateway.On("RemoveFile", r.action.HomeCurrentDir()).Return(os.ErrNotExist).Once()
 
-	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version).Return("", nil).Once()
-	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(osNilFile, errors.New("error")).Once()
+	err := r.sharedSvc.RemoveCurrentVersion(r.ctx, r.action)
 
-	err := r.sharedSvc.Checksum(r.ctx, r.action)
+	r.NoError(err)
+	r.Empty(r.action.PreviousCurrent)
+}
+
+func (r *sharedServiceSuite) TestRemoveCurrentVersionError() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return(r.action.HomeVersionDir(), nil).Once()
+	r.osGateway.On("RemoveFile", r.action.HomeCurrentDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.RemoveCurrentVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumOpenFile), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveCurrentVersion, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestChecksumMismatchError() {
-	r.httpGateway.On("GetChecksum", r.ctx, r.action.Version).Return("", nil).Once()
-	r.osGateway.On("OpenFile", r.action.DownloadFile()).Return(os.CreateTemp("", "")).Once()
+func (r *sharedServiceSuite) TestRestoreCurrentVersionRelink() {
+	r.action.PreviousCurrent = "/home/fake/.govm/versions/go1.19.2"
+	r.osGateway.On("CreateSymlink", "/home/fake/.govm/versions/go1.19.2", r.action.HomeCurrentDir()).Return(nil).Once()
 
-	err := r.sharedSvc.Checksum(r.ctx, r.action)
+	err := r.sharedSvc.RestoreCurrentVersion(r.ctx, r.action)
 
-	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeChecksumMismatch), err)
+	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestRemoveVersionSuccess() {
-	r.osGateway.On("RemoveDir", r.action.HomeGoDir()).Return(nil).Once()
+func (r *sharedServiceSuite) TestRestoreCurrentVersionUnlink() {
+	r.osGateway.On("RemoveFile", r.action.HomeCurrentDir()).Return(nil).Once()
 
-	err := r.sharedSvc.RemoveVersion(r.ctx, r.action)
+	err := r.sharedSvc.RestoreCurrentVersion(r.ctx, r.action)
 
 	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestRemoveVersionError() {
-	r.osGateway.On("RemoveDir", r.action.HomeGoDir()).Return(errors.New("error")).Once()
+func (r *sharedServiceSuite) TestRestoreCurrentVersionError() {
+	r.action.PreviousCurrent = "/home/fake/.govm/versions/go1.19.2"
+	r.osGateway.On("CreateSymlink", "/home/fake/.govm/versions/go1.19.2", r.action.HomeCurrentDir()).Return(errors.New("error")).Once()
 
-	err := r.sharedSvc.RemoveVersion(r.ctx, r.action)
+	err := r.sharedSvc.RestoreCurrentVersion(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveVersion), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRollback, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestUntarFilesSuccess() {
-	r.osGateway.On("CreateDir", r.action.HomeGovmDir(), fileModeType).Return(nil).Once()
-	r.osGateway.On("Untar", r.action.DownloadFile(), r.action.HomeGovmDir()).Return(nil).Once()
-	r.osGateway.On("RemoveFile", r.action.DownloadFile()).Return(nil).Once()
+func (r *sharedServiceSuite) TestCreateShimsSuccess() {
+	r.osGateway.On("GetExecutable").Return("/home/fake/.govm/bin/govm", nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeShimsDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("CreateSymlink", "/home/fake/.govm/bin/govm", filepath.Join(r.action.HomeShimsDir(), "go")).Return(nil).Once()
+	r.osGateway.On("CreateSymlink", "/home/fake/.govm/bin/govm", filepath.Join(r.action.HomeShimsDir(), "gofmt")).Return(nil).Once()
 
-	err := r.sharedSvc.UntarFiles(r.ctx, r.action)
+	err := r.sharedSvc.CreateShims(r.ctx, r.action)
 
 	r.NoError(err)
 }
 
-func (r *sharedServiceSuite) TestUntarFilesCreateDirError() {
-	r.osGateway.On("CreateDir", mock.AnythingOfType("string"), fileModeType).Return(errors.New("error")).Once()
+func (r *sharedServiceSuite) TestCreateShimsGetExecutableError() {
+	r.osGateway.On("GetExecutable").Return("", errors.New("error")).Once()
 
-	err := r.sharedSvc.UntarFiles(r.ctx, r.action)
+	err := r.sharedSvc.CreateShims(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeUntarCreateDir), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeGetExecutable, errors.New("error")), err)
 }
 
-func (r *sharedServiceSuite) TestUntarFilesExtractError() {
-	r.osGateway.On("CreateDir", r.action.HomeGovmDir(), fileModeType).Return(nil).Once()
-	r.osGateway.On("Untar", r.action.DownloadFile(), r.action.HomeGovmDir()).Return(errors.New("error")).Once()
+func (r *sharedServiceSuite) TestCreateShimsCreateDirError() {
+	r.osGateway.On("GetExecutable").Return("/home/fake/.govm/bin/govm", nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeShimsDir(), fileModeType).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.CreateShims(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCreateShims, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestCreateShimsCreateSymlinkError() {
+	r.osGateway.On("GetExecutable").Return("/home/fake/.govm/bin/govm", nil).Once()
+	r.osGateway.On("CreateDir", r.action.HomeShimsDir(), fileModeType).Return(nil).Once()
+	r.osGateway.On("CreateSymlink", "/home/fake/.govm/bin/govm", filepath.Join(r.action.HomeShimsDir(), "go")).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.CreateShims(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCreateShims, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestRunWithVersionSuccess() {
+	env := []string{
+		"GOROOT=" + r.action.HomeVersionDir(),
+		"PATH=" + r.action.HomeVersionBinDir() + string(os.PathListSeparator) + pathEnv,
+	}
+
+	r.osGateway.On("GetEnv", "PATH").Return(pathEnv).Once()
+	r.osGateway.On("RunCommand", filepath.Join(r.action.HomeVersionBinDir(), "go"), []string{"test", "./..."}, env).Return(1, nil).Once()
 
-	err := r.sharedSvc.UntarFiles(r.ctx, r.action)
+	code, err := r.sharedSvc.RunWithVersion(r.ctx, r.action, "go", []string{"test", "./..."})
+
+	r.NoError(err)
+	r.Equal(1, code)
+}
+
+func (r *sharedServiceSuite) TestRunWithVersionError() {
+	r.osGateway.On("GetEnv", "PATH").Return(pathEnv).Once()
+	r.osGateway.On("RunCommand", "make", []string{"test"}, mock.Anything).Return(-1, errors.New("error")).Once()
+
+	code, err := r.sharedSvc.RunWithVersion(r.ctx, r.action, "make", []string{"test"})
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeUntarExtract), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRunCommand, errors.New("error")), err)
+	r.Equal(-1, code)
 }
 
 func (r *sharedServiceSuite) TestAddToPathStatError() {
@@ -246,7 +1322,7 @@ func (r *sharedServiceSuite) TestAddToPathStatError() {
 
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathStat), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathStat, errors.New("error")), err)
 }
 
 func (r *sharedServiceSuite) TestAddToPathReadFileError() {
@@ -258,7 +1334,7 @@ func (r *sharedServiceSuite) TestAddToPathReadFileError() {
 
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathRead), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathRead, errors.New("error")), err)
 }
 
 func (r *sharedServiceSuite) TestAddToPathWriteFileError() {
@@ -271,7 +1347,7 @@ func (r *sharedServiceSuite) TestAddToPathWriteFileError() {
 
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathWrite), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathWrite, errors.New("error")), err)
 }
 
 func (r *sharedServiceSuite) TestAddToPathNoShellsFoundError() {
@@ -282,7 +1358,7 @@ func (r *sharedServiceSuite) TestAddToPathNoShellsFoundError() {
 
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathNoShellsFound), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeAddToPathNoShellsFound, nil), err)
 }
 
 func (r *sharedServiceSuite) TestAddToPathWithEmptyShellEnvVarSuccess() {
@@ -296,6 +1372,7 @@ func (r *sharedServiceSuite) TestAddToPathWithEmptyShellEnvVarSuccess() {
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
 	r.NoError(err)
+	r.True(r.action.PathUpdated)
 }
 
 func (r *sharedServiceSuite) TestAddToPathWithFilledShellEnvVarSuccess() {
@@ -309,43 +1386,99 @@ func (r *sharedServiceSuite) TestAddToPathWithFilledShellEnvVarSuccess() {
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
 	r.NoError(err)
+	r.True(r.action.PathUpdated)
+	r.Equal(map[string][]byte{filepath.Join(r.action.HomeDir, ".bashrc"): []byte("export PATH=$PATH:/home/fake/go/bin")}, r.action.RunCommands)
+}
+
+func (r *sharedServiceSuite) TestAddToPathRemovesLegacyExport() {
+	rcfPath := filepath.Join(r.action.HomeDir, ".bashrc")
+	r.fileInfoMock.On("IsDir").Return(true).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(pathEnv, nil).Once()
+	r.osGateway.On("GetEnv", "SHELL").Return(bashDir, nil).Once()
+	r.osGateway.On("Stat", rcfPath).Return(r.fileInfoMock, nil).Once()
+	r.osGateway.On("ReadFile", rcfPath).Return([]byte("alias ll='ls -l'\n"+r.action.LegacyExport()), nil).Once()
+	r.osGateway.On("WriteFile", rcfPath, []byte("alias ll='ls -l'\n\n"+r.action.Export()), fileModeType).Return(nil).Once()
+
+	err := r.sharedSvc.AddToPath(r.ctx, r.action)
+
+	r.NoError(err)
+	r.True(r.action.PathUpdated)
 }
 
 func (r *sharedServiceSuite) TestAddToPatWithGoAlreadyInPathSuccess() {
-	r.osGateway.On("GetEnv", "PATH").Return("/home/fake/.govm/go/bin", nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return("/home/fake/.govm/shims", nil).Once()
+
+	err := r.sharedSvc.AddToPath(r.ctx, r.action)
+
+	r.NoError(err)
+	r.False(r.action.PathUpdated)
+}
+
+func (r *sharedServiceSuite) TestAddToPathWithShellIntegrationDisabled() {
+	r.config.ShellIntegration = domain.NoShellIntegration
 
 	err := r.sharedSvc.AddToPath(r.ctx, r.action)
 
+	r.NoError(err)
+	r.False(r.action.PathUpdated)
+}
+
+func (r *sharedServiceSuite) TestRestoreRunCommandsSuccess() {
+	r.action.SaveRunCommand("/home/fake/.bashrc", []byte("bash"))
+	r.action.SaveRunCommand("/home/fake/.zshrc", []byte("zsh"))
+	r.osGateway.On("WriteFile", "/home/fake/.bashrc", []byte("bash"), fileModeType).Return(nil).Once()
+	r.osGateway.On("WriteFile", "/home/fake/.zshrc", []byte("zsh"), fileModeType).Return(nil).Once()
+
+	err := r.sharedSvc.RestoreRunCommands(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestRestoreRunCommandsError() {
+	r.action.SaveRunCommand("/home/fake/.bashrc", []byte("bash"))
+	r.osGateway.On("WriteFile", "/home/fake/.bashrc", []byte("bash"), fileModeType).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.RestoreRunCommands(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRollback, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestRemoveFromPathWithShellIntegrationDisabled() {
+	r.config.ShellIntegration = domain.NoShellIntegration
+
+	err := r.sharedSvc.RemoveFromPath(r.ctx, r.action)
+
 	r.NoError(err)
 }
 
 func (r *sharedServiceSuite) TestRemoveFromPathNoShellCommandsFoundError() {
 	r.fileInfoMock.On("IsDir").Return(true)
-	r.osGateway.On("GetEnv", "PATH").Return(goBinDir, nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(shimsDir, nil).Once()
 	r.osGateway.On("GetEnv", "SHELL").Return("", nil).Once()
 	r.osGateway.On("Stat", mock.Anything).Return(r.fileInfoMock, errors.New("error")).Times(8)
 
 	err := r.sharedSvc.RemoveFromPath(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathNoShellsFound), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathNoShellsFound, nil), err)
 }
 
 func (r *sharedServiceSuite) TestRemoveFromPathStatError() {
 	r.fileInfoMock.On("IsDir").Return(true)
-	r.osGateway.On("GetEnv", "PATH").Return(goBinDir, nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(shimsDir, nil).Once()
 	r.osGateway.On("GetEnv", "SHELL").Return(bashDir, nil).Once()
 	r.osGateway.On("Stat", mock.Anything).Return(r.fileInfoMock, errors.New("error")).Once()
 
 	err := r.sharedSvc.RemoveFromPath(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathStat), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathStat, errors.New("error")), err)
 }
 
 func (r *sharedServiceSuite) TestRemoveFromShellRunCommandsReadError() {
 	r.fileInfoMock.On("IsDir").Return(true)
-	r.osGateway.On("GetEnv", "PATH").Return(goBinDir, nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(shimsDir, nil).Once()
 	r.osGateway.On("GetEnv", "SHELL").Return(bashDir, nil).Once()
 	r.osGateway.On("Stat", mock.Anything).Return(r.fileInfoMock, nil).Once()
 	r.osGateway.On("ReadFile", mock.Anything).Return([]byte{}, errors.New("error")).Once()
@@ -353,12 +1486,12 @@ func (r *sharedServiceSuite) TestRemoveFromShellRunCommandsReadError() {
 	err := r.sharedSvc.RemoveFromPath(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathRead), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathRead, errors.New("error")), err)
 }
 
 func (r *sharedServiceSuite) TestRemoveFromShellRunCommandsWriteError() {
 	r.fileInfoMock.On("IsDir").Return(true)
-	r.osGateway.On("GetEnv", "PATH").Return(goBinDir, nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(shimsDir, nil).Once()
 	r.osGateway.On("GetEnv", "SHELL").Return(bashDir, nil).Once()
 	r.osGateway.On("Stat", mock.Anything).Return(r.fileInfoMock, nil).Once()
 	r.osGateway.On("ReadFile", mock.Anything).Return([]byte("content"), nil).Once()
@@ -367,7 +1500,7 @@ func (r *sharedServiceSuite) TestRemoveFromShellRunCommandsWriteError() {
 	err := r.sharedSvc.RemoveFromPath(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathWrite), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeRemoveFromPathWrite, errors.New("error")), err)
 }
 
 func (r *sharedServiceSuite) TestSuccessAlreadyRemovedFromPath() {
@@ -378,9 +1511,23 @@ func (r *sharedServiceSuite) TestSuccessAlreadyRemovedFromPath() {
 	r.NoError(err)
 }
 
+func (r *sharedServiceSuite) TestSuccessRemovingLegacyExportFromPath() {
+	rcfPath := filepath.Join(r.action.HomeDir, ".bashrc")
+	r.fileInfoMock.On("IsDir").Return(true)
+	r.osGateway.On("GetEnv", "PATH").Return("/usr/bin:"+filepath.Join(r.action.LegacyHomeGoDir(), "bin"), nil).Once()
+	r.osGateway.On("GetEnv", "SHELL").Return(bashDir, nil).Once()
+	r.osGateway.On("Stat", rcfPath).Return(r.fileInfoMock, nil).Once()
+	r.osGateway.On("ReadFile", rcfPath).Return([]byte("alias ll='ls -l'\n"+r.action.LegacyExport()), nil).Once()
+	r.osGateway.On("WriteFile", rcfPath, []byte("alias ll='ls -l'\n"), fileModeType).Return(nil).Once()
+
+	err := r.sharedSvc.RemoveFromPath(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
 func (r *sharedServiceSuite) TestSuccessRemovingFromPathWithEmptyShellEnvVar() {
 	r.fileInfoMock.On("IsDir").Return(true)
-	r.osGateway.On("GetEnv", "PATH").Return(goBinDir, nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(shimsDir, nil).Once()
 	r.osGateway.On("GetEnv", "SHELL").Return("", nil).Once()
 	r.osGateway.On("Stat", mock.Anything).Return(r.fileInfoMock, nil).Times(8)
 	r.osGateway.On("ReadFile", mock.Anything).Return([]byte("path content"), nil).Times(8)
@@ -393,7 +1540,7 @@ func (r *sharedServiceSuite) TestSuccessRemovingFromPathWithEmptyShellEnvVar() {
 
 func (r *sharedServiceSuite) TestSuccessRemovingFromPathWithFilledShellEnvVar() {
 	r.fileInfoMock.On("IsDir").Return(true)
-	r.osGateway.On("GetEnv", "PATH").Return(goBinDir, nil).Once()
+	r.osGateway.On("GetEnv", "PATH").Return(shimsDir, nil).Once()
 	r.osGateway.On("GetEnv", "SHELL").Return(bashDir, nil).Once()
 	r.osGateway.On("Stat", mock.Anything).Return(r.fileInfoMock, nil).Once()
 	r.osGateway.On("ReadFile", mock.Anything).Return([]byte("path content"), nil).Once()
@@ -405,16 +1552,16 @@ func (r *sharedServiceSuite) TestSuccessRemovingFromPathWithFilledShellEnvVar()
 }
 
 func (r *sharedServiceSuite) TestCheckInstalledVersionSuccess() {
-	r.osGateway.On("GetInstalledGoVersion").Return("1.19.2", nil).Once()
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("/home/fake/.govm/versions/go1.19.2", nil).Once()
 
 	err := r.sharedSvc.CheckInstalledVersion(r.ctx, r.action)
 
 	r.NoError(err)
-	r.Equal("1.19.2", r.action.InstalledVersion)
+	r.Equal("go1.19.2", r.action.InstalledVersion)
 }
 
-func (r *sharedServiceSuite) TestCheckInstalledVersionError() {
-	r.osGateway.On("GetInstalledGoVersion").Return("", errors.New("error")).Once()
+func (r *sharedServiceSuite) TestCheckInstalledVersionNoCurrentVersion() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", os.ErrNotExist).Once()
 
 	err := r.sharedSvc.CheckInstalledVersion(r.ctx, r.action)
 
@@ -422,6 +1569,15 @@ func (r *sharedServiceSuite) TestCheckInstalledVersionError() {
 	r.Equal(domain.NewNoGoInstallationsFoundError(), err)
 }
 
+func (r *sharedServiceSuite) TestCheckInstalledVersionError() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", errors.New("error")).Once()
+
+	err := r.sharedSvc.CheckInstalledVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeGetCurrentVersion, errors.New("error")), err)
+}
+
 func (r *sharedServiceSuite) TestCheckAvailableUpdatesSuccess() {
 
 	tests := []struct {
@@ -500,7 +1656,7 @@ func (r *sharedServiceSuite) TestCheckAvailableUpdatesSuccess() {
 				}
 			}
 
-			r.httpGateway.On("GetVersions", r.ctx).Return(availableVersions, nil).Once()
+			r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(availableVersions, nil).Once()
 
 			err := r.sharedSvc.CheckAvailableUpdates(r.ctx, action)
 
@@ -516,6 +1672,78 @@ func (r *sharedServiceSuite) TestCheckAvailableUpdatesSuccess() {
 	}
 }
 
+func (r *sharedServiceSuite) TestCheckAvailableUpdatesUnstable() {
+	availableVersions := domain.VersionsResponse{
+		Versions: []domain.VersionResponse{
+			{Version: "go1.23rc2"},
+			{Version: "go1.23rc1"},
+			{Version: "go1.23beta1"},
+			{Version: "go1.22.5", Stable: true},
+			{Version: "go1.22.0", Stable: true},
+			{Version: "go1.22rc1"},
+			{Version: "go1.21.12", Stable: true},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		installedVersion string
+		updateStrategy   domain.UpdateStrategy
+		unstable         bool
+		expectedVersion  string
+		expectedError    error
+	}{
+		{
+			name:             "Stable Only",
+			installedVersion: "go1.21.12",
+			updateStrategy:   domain.MinorStrategy,
+			expectedVersion:  "go1.22.5",
+		},
+		{
+			name:             "Unstable Asked",
+			installedVersion: "go1.21.12",
+			updateStrategy:   domain.MinorStrategy,
+			unstable:         true,
+			expectedVersion:  "go1.23rc2",
+		},
+		{
+			name:             "Release Candidate Installed",
+			installedVersion: "go1.22rc1",
+			updateStrategy:   domain.PatchStrategy,
+			expectedVersion:  "go1.22.5",
+		},
+		{
+			name:             "Newer Release Candidate",
+			installedVersion: "go1.23beta1",
+			updateStrategy:   domain.PatchStrategy,
+			unstable:         true,
+			expectedVersion:  "go1.23rc2",
+		},
+		{
+			name:             "No Stable Release Yet",
+			installedVersion: "go1.23rc1",
+			updateStrategy:   domain.MinorStrategy,
+			expectedError:    domain.NewNoUpdatesAvailableError(domain.MinorStrategy, "go1.23rc1"),
+		},
+	}
+
+	for _, tc := range tests {
+		r.Run(tc.name, func() {
+			action := &domain.Action{
+				InstalledVersion: tc.installedVersion,
+				UpdateStrategy:   tc.updateStrategy,
+				Unstable:         tc.unstable,
+			}
+			r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(availableVersions, nil).Once()
+
+			err := r.sharedSvc.CheckAvailableUpdates(r.ctx, action)
+
+			r.Equal(tc.expectedError, err)
+			r.Equal(tc.expectedVersion, action.Version)
+		})
+	}
+}
+
 func (r *sharedServiceSuite) TestCheckAvailableUpdatesGetVersionsError() {
 
 	action := &domain.Action{
@@ -523,49 +1751,269 @@ func (r *sharedServiceSuite) TestCheckAvailableUpdatesGetVersionsError() {
 		UpdateStrategy:   domain.PatchStrategy,
 	}
 
-	r.httpGateway.On("GetVersions", r.ctx).Return(domain.VersionsResponse{}, errors.New("error")).Once()
+	r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(domain.VersionsResponse{}, errors.New("error")).Once()
 
 	err := r.sharedSvc.CheckAvailableUpdates(r.ctx, action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeListVersions), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeListVersions, errors.New("error")), err)
 	r.Empty(action.Version)
 }
 
 func (r *sharedServiceSuite) TestGetAvailableGoVersionsSuccess() {
-	r.httpGateway.On("GetVersions", r.ctx).Return(domain.VersionsResponse{}, nil).Once()
+	r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(domain.VersionsResponse{}, nil).Once()
 
-	available, err := r.sharedSvc.GetAvailableGoVersions(r.ctx)
+	available, err := r.sharedSvc.GetAvailableGoVersions(r.ctx, r.action)
 
 	r.NoError(err)
 	r.Empty(available.Versions)
 }
 
+func (r *sharedServiceSuite) TestGetAvailableGoVersionsUnstable() {
+	versions := domain.VersionsResponse{
+		Versions: []domain.VersionResponse{
+			{Version: "go1.23rc1"},
+			{Version: "go1.22.5", Stable: true},
+		},
+	}
+	r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(versions, nil).Twice()
+
+	stable, err := r.sharedSvc.GetAvailableGoVersions(r.ctx, &domain.Action{})
+	r.NoError(err)
+	r.Equal([]string{"go1.22.5"}, stable.StringSlice())
+
+	all, err := r.sharedSvc.GetAvailableGoVersions(r.ctx, &domain.Action{Unstable: true})
+	r.NoError(err)
+	r.Equal([]string{"go1.23rc1", "go1.22.5"}, all.StringSlice())
+}
+
 func (r *sharedServiceSuite) TestGetAvailableGoVersionsError() {
-	r.httpGateway.On("GetVersions", r.ctx).Return(domain.VersionsResponse{}, errors.New("error")).Once()
+	r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(domain.VersionsResponse{}, errors.New("error")).Once()
 
-	available, err := r.sharedSvc.GetAvailableGoVersions(r.ctx)
+	available, err := r.sharedSvc.GetAvailableGoVersions(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewUnexpectedError(domain.ErrCodeListVersions), err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeListVersions, errors.New("error")), err)
 	r.Empty(available.Versions)
 }
 
-func (r *sharedServiceSuite) TestGetInstalledGoVersionSuccess() {
-	r.osGateway.On("GetInstalledGoVersion").Return("1.2.2", nil).Once()
+func (r *sharedServiceSuite) TestGetAvailableGoVersionsOfflineError() {
+	r.httpGateway.On("GetVersions", r.ctx, domain.HostPlatform()).Return(domain.VersionsResponse{}, gateway.ErrOffline).Once()
+
+	available, err := r.sharedSvc.GetAvailableGoVersions(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewNotAvailableOfflineError("the Go release index"), err)
+	r.Empty(available.Versions)
+}
+
+func (r *sharedServiceSuite) TestGetLocalGoVersionsSuccess() {
+	dir := r.T().TempDir()
+	os.Mkdir(filepath.Join(dir, "go1.21.0"), 0755)
+	os.Mkdir(filepath.Join(dir, "go1.22.3"), 0755)
+	os.Mkdir(filepath.Join(dir, ".go1.22.4.staging"), 0755)
+	os.WriteFile(filepath.Join(dir, "stray"), []byte{}, 0644)
+	entries, _ := os.ReadDir(dir)
+
+	r.osGateway.On("ReadDir", r.action.HomeVersionsDir()).Return(entries, nil).Once()
+
+	local, err := r.sharedSvc.GetLocalGoVersions(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal([]string{"go1.21.0", "go1.22.3"}, local)
+}
+
+func (r *sharedServiceSuite) TestGetLocalGoVersionsNotExists() {
+	r.osGateway.On("ReadDir", r.action.HomeVersionsDir()).Return([]os.DirEntry{}, os.ErrNotExist).Once()
 
-	installed, err := r.sharedSvc.GetInstalledGoVersion(r.ctx)
+	local, err := r.sharedSvc.GetLocalGoVersions(r.ctx, r.action)
 
 	r.NoError(err)
-	r.Equal("1.2.2", installed)
+	r.Empty(local)
 }
 
-func (r *sharedServiceSuite) TestGetInstalledGoVersionError() {
-	r.osGateway.On("GetInstalledGoVersion").Return("", errors.New("error")).Once()
+func (r *sharedServiceSuite) TestGetLocalGoVersionsError() {
+	r.osGateway.On("ReadDir", r.action.HomeVersionsDir()).Return([]os.DirEntry{}, errors.New("error")).Once()
 
-	installed, err := r.sharedSvc.GetInstalledGoVersion(r.ctx)
+	local, err := r.sharedSvc.GetLocalGoVersions(r.ctx, r.action)
 
 	r.Error(err)
-	r.Equal(domain.NewNoGoInstallationsFoundError(), err)
-	r.Empty(installed)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeListLocalVersions, errors.New("error")), err)
+	r.Empty(local)
+}
+
+func (r *sharedServiceSuite) TestGetCurrentGoVersionSuccess() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("/home/fake/.govm/versions/go1.22.3", nil).Once()
+
+	current, err := r.sharedSvc.GetCurrentGoVersion(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal("go1.22.3", current)
+}
+
+func (r *sharedServiceSuite) TestGetCurrentGoVersionNotExists() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", os.ErrNotExist).Once()
+
+	current, err := r.sharedSvc.GetCurrentGoVersion(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Empty(current)
+}
+
+func (r *sharedServiceSuite) TestGetCurrentGoVersionError() {
+	r.osGateway.On("ReadSymlink", r.action.HomeCurrentDir()).Return("", errors.New("error")).Once()
+
+	current, err := r.sharedSvc.GetCurrentGoVersion(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeGetCurrentVersion, errors.New("error")), err)
+	r.Empty(current)
+}
+
+func (r *sharedServiceSuite) TestGetCachedArchivesSuccess() {
+	dir := r.T().TempDir()
+	older := filepath.Join(dir, "aaa", "go1.21.0.linux-amd64.tar.gz")
+	newer := filepath.Join(dir, "bbb", "go1.22.3.linux-amd64.tar.gz")
+	os.MkdirAll(filepath.Dir(older), 0755)
+	os.MkdirAll(filepath.Dir(newer), 0755)
+	os.WriteFile(older, []byte("older"), 0644)
+	os.WriteFile(newer, []byte("newer!"), 0644)
+	os.WriteFile(filepath.Join(dir, "go1.23.0.linux-amd64.tar.gz.part"), []byte{}, 0644)
+	os.Chtimes(older, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+	r.action.HomeDir = "/fake/home"
+	entries, _ := os.ReadDir(dir)
+	olderEntries, _ := os.ReadDir(filepath.Dir(older))
+	newerEntries, _ := os.ReadDir(filepath.Dir(newer))
+
+	r.osGateway.On("ReadDir", r.action.CacheDir()).Return(entries, nil).Once()
+	r.osGateway.On("ReadDir", filepath.Join(r.action.CacheDir(), "aaa")).Return(olderEntries, nil).Once()
+	r.osGateway.On("ReadDir", filepath.Join(r.action.CacheDir(), "bbb")).Return(newerEntries, nil).Once()
+
+	archives, err := r.sharedSvc.GetCachedArchives(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Len(archives, 2)
+	r.Equal("bbb", archives[0].Checksum)
+	r.Equal("go1.22.3.linux-amd64.tar.gz", archives[0].Filename)
+	r.Equal(int64(6), archives[0].Size)
+	r.Equal("aaa", archives[1].Checksum)
+	r.Equal("go1.21.0.linux-amd64.tar.gz", archives[1].Filename)
+}
+
+func (r *sharedServiceSuite) TestGetCachedArchivesNotExists() {
+	r.osGateway.On("ReadDir", r.action.CacheDir()).Return([]os.DirEntry{}, os.ErrNotExist).Once()
+
+	archives, err := r.sharedSvc.GetCachedArchives(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Empty(archives)
+}
+
+func (r *sharedServiceSuite) TestGetCachedArchivesError() {
+	r.osGateway.On("ReadDir", r.action.CacheDir()).Return([]os.DirEntry{}, errors.New("error")).Once()
+
+	archives, err := r.sharedSvc.GetCachedArchives(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCacheList, errors.New("error")), err)
+	r.Empty(archives)
+}
+
+func (r *sharedServiceSuite) TestRemoveCachedArchiveSuccess() {
+	archive := domain.CachedArchive{Checksum: "abc123"}
+
+	r.osGateway.On("RemoveDir", filepath.Join(r.action.CacheDir(), "abc123")).Return(nil).Once()
+
+	err := r.sharedSvc.RemoveCachedArchive(r.ctx, r.action, archive)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestRemoveCachedArchiveError() {
+	archive := domain.CachedArchive{Checksum: "abc123"}
+
+	r.osGateway.On("RemoveDir", filepath.Join(r.action.CacheDir(), "abc123")).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.RemoveCachedArchive(r.ctx, r.action, archive)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCacheRemove, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestCleanCacheSuccess() {
+	r.osGateway.On("RemoveDir", r.action.CacheDir()).Return(nil).Once()
+
+	err := r.sharedSvc.CleanCache(r.ctx, r.action)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestCleanCacheError() {
+	r.osGateway.On("RemoveDir", r.action.CacheDir()).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.CleanCache(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeCacheRemove, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestReadConfigSuccess() {
+	r.action.HomeDir = "/home/fake"
+	r.osGateway.On("ReadConfig", "/home/fake/.govm/config.toml").Return(domain.Config{Color: domain.NeverColor}, nil).Once()
+
+	config, err := r.sharedSvc.ReadConfig(r.ctx, r.action)
+
+	r.NoError(err)
+	r.Equal(domain.Config{Color: domain.NeverColor}, config)
+}
+
+func (r *sharedServiceSuite) TestReadConfigError() {
+	r.osGateway.On("ReadConfig", r.action.ConfigFile()).Return(domain.Config{}, errors.New("error")).Once()
+
+	_, err := r.sharedSvc.ReadConfig(r.ctx, r.action)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeReadConfig, errors.New("error")), err)
+}
+
+func (r *sharedServiceSuite) TestWriteConfigSuccess() {
+	config := domain.Config{Color: domain.NeverColor}
+	r.osGateway.On("WriteConfig", r.action.ConfigFile(), config).Return(nil).Once()
+
+	err := r.sharedSvc.WriteConfig(r.ctx, r.action, config)
+
+	r.NoError(err)
+}
+
+func (r *sharedServiceSuite) TestWriteConfigError() {
+	config := domain.Config{Color: domain.NeverColor}
+	r.osGateway.On("WriteConfig", r.action.ConfigFile(), config).Return(errors.New("error")).Once()
+
+	err := r.sharedSvc.WriteConfig(r.ctx, r.action, config)
+
+	r.Error(err)
+	r.Equal(domain.NewUnexpectedError(domain.ErrCodeWriteConfig, errors.New("error")), err)
+}
+
+// streamed returns the Run functions of the StreamVersion and UntarReader mocks, which
+// the service calls concurrently. testify prints the arguments of a call, the pipe
+// between them included, so each waits for the other to be called before using it.
+// stream writes content, and untar reads the archive to the end when read is set.
+func (r *sharedServiceSuite) streamed(content string, read bool) (stream func(mock.Arguments), untar func(mock.Arguments)) {
+	streamCalled, untarCalled := make(chan struct{}), make(chan struct{})
+	stream = func(args mock.Arguments) {
+		close(streamCalled)
+		<-untarCalled
+		if content != "" {
+			_, _ = args.Get(2).(io.Writer).Write([]byte(content))
+		}
+	}
+	untar = func(args mock.Arguments) {
+		close(untarCalled)
+		<-streamCalled
+		if read {
+			_, _ = io.ReadAll(args.Get(1).(io.Reader))
+		}
+	}
+	return stream, untar
 }
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/sbonaiva/govm/internal/domain"
+)
+
+const (
+	progressBarWidth    = 20
+	progressRefreshRate = 100 * time.Millisecond
+	eraseLine           = "\r\033[K"
+)
+
+// Progress reports the advance of the steps of a handler.
+type Progress interface {
+	Start()
+	Stop()
+	// Step shows message as the step in progress.
+	Step(message string)
+	// Transfer reports that written of total bytes of the current step were
+	// transferred. total is zero or less when unknown.
+	Transfer(written int64, total int64)
+}
+
+type progress struct {
+	mu          sync.Mutex
+	writer      io.Writer
+	interactive bool
+	spn         *spinner.Spinner
+	message     string
+	transfer    bool
+	started     time.Time
+	rendered    time.Time
+}
+
+// NewProgress reports steps with a spinner on writer. When interactive, transfers are
+// shown as a bar with size, rate and ETA; otherwise only the spinner is used.
+func NewProgress(writer io.Writer, interactive bool) Progress {
+	return &progress{
+		writer:      writer,
+		interactive: interactive,
+		spn:         spinner.New(spinner.CharSets[11], progressRefreshRate, spinner.WithWriter(writer)),
+	}
+}
+
+func (r *progress) Start() {
+	r.spn.Start()
+}
+
+func (r *progress) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spn.Stop()
+	if r.transfer {
+		fmt.Fprint(r.writer, eraseLine)
+		r.transfer = false
+	}
+}
+
+func (r *progress) Step(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.message = message
+	r.spn.Lock()
+	r.spn.Suffix = message
+	r.spn.Unlock()
+
+	if r.transfer {
+		fmt.Fprint(r.writer, eraseLine)
+		r.transfer = false
+		r.spn.Start()
+	}
+}
+
+func (r *progress) Transfer(written int64, total int64) {
+	if !r.interactive {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if !r.transfer {
+		r.spn.Stop()
+		r.transfer = true
+		r.started = now
+	} else if now.Sub(r.rendered) < progressRefreshRate && written != total {
+		return
+	}
+	r.rendered = now
+
+	fmt.Fprintf(r.writer, "%s%s %s", eraseLine, r.message, FormatTransfer(written, total, now.Sub(r.started)))
+}
+
+// FormatTransfer describes a transfer of written of total bytes that took elapsed so
+// far, e.g. "[=========>          ]  48% 33.6 MiB/70.1 MiB 2.1 MiB/s ETA 17s".
+func FormatTransfer(written int64, total int64, elapsed time.Duration) string {
+	var b strings.Builder
+
+	if total > 0 {
+		filled := int(written * progressBarWidth / total)
+		bar := strings.Repeat("=", filled)
+		if filled < progressBarWidth {
+			bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+		}
+		fmt.Fprintf(&b, "[%s] %3d%% %s/%s", bar, written*100/total, domain.FormatSize(written), domain.FormatSize(total))
+	} else {
+		b.WriteString(domain.FormatSize(written))
+	}
+
+	if elapsed <= 0 {
+		return b.String()
+	}
+
+	rate := float64(written) / elapsed.Seconds()
+	fmt.Fprintf(&b, " %s/s", domain.FormatSize(int64(rate)))
+
+	if total > 0 && written < total && rate > 0 {
+		eta := time.Duration(float64(total-written) / rate * float64(time.Second))
+		fmt.Fprintf(&b, " ETA %s", eta.Round(time.Second))
+	}
+
+	return b.String()
+}
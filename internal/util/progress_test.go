@@ -0,0 +1,79 @@
+package util_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTransfer(t *testing.T) {
+	tests := []struct {
+		name     string
+		written  int64
+		total    int64
+		elapsed  time.Duration
+		expected string
+	}{
+		{
+			name:     "Not Started",
+			written:  0,
+			total:    1024,
+			expected: "[>                   ]   0% 0 B/1.0 KiB",
+		},
+		{
+			name:     "In Progress",
+			written:  35 * 1024 * 1024,
+			total:    70 * 1024 * 1024,
+			elapsed:  10 * time.Second,
+			expected: "[==========>         ]  50% 35.0 MiB/70.0 MiB 3.5 MiB/s ETA 10s",
+		},
+		{
+			name:     "Completed",
+			written:  70 * 1024 * 1024,
+			total:    70 * 1024 * 1024,
+			elapsed:  20 * time.Second,
+			expected: "[====================] 100% 70.0 MiB/70.0 MiB 3.5 MiB/s",
+		},
+		{
+			name:     "Unknown Total",
+			written:  2 * 1024 * 1024,
+			total:    -1,
+			elapsed:  2 * time.Second,
+			expected: "2.0 MiB 1.0 MiB/s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, util.FormatTransfer(tt.written, tt.total, tt.elapsed))
+		})
+	}
+}
+
+func TestProgressInteractive(t *testing.T) {
+	var buf bytes.Buffer
+	progress := util.NewProgress(&buf, true)
+
+	progress.Start()
+	progress.Step(" Downloading files...")
+	progress.Transfer(1024, 1024)
+	progress.Step(" Verifying checksum...")
+	progress.Stop()
+
+	assert.Equal(t, "\r\033[K Downloading files... [====================] 100% 1.0 KiB/1.0 KiB\r\033[K", buf.String())
+}
+
+func TestProgressNotInteractive(t *testing.T) {
+	var buf bytes.Buffer
+	progress := util.NewProgress(&buf, false)
+
+	progress.Start()
+	progress.Step(" Downloading files...")
+	progress.Transfer(1024, 1024)
+	progress.Stop()
+
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// Renderer prints the results of the commands for people, or as data in the format
+// asked with --output, so they can be read by other tools.
+type Renderer interface {
+	// Structured reports whether results are rendered as data, in which case
+	// messages meant for people are left out.
+	Structured() bool
+	// Result renders result as data, or calls text to print it for people.
+	Result(result any, text func())
+	// Error renders err as data along with its code, or prints it for people. With
+	// --verbose, the causes of err are rendered too.
+	Error(err error)
+}
+
+// RenderConfig is set from --output and --verbose.
+type RenderConfig struct {
+	Format  domain.OutputFormat
+	Verbose bool
+}
+
+type renderer struct {
+	config *RenderConfig
+}
+
+// NewRenderer renders as set in config, which is only known once the flags are
+// parsed.
+func NewRenderer(config *RenderConfig) Renderer {
+	return &renderer{
+		config: config,
+	}
+}
+
+func (r *renderer) Structured() bool {
+	return r.config.Format == domain.JSONOutput || r.config.Format == domain.YAMLOutput
+}
+
+func (r *renderer) Result(result any, text func()) {
+	if !r.Structured() {
+		text()
+		return
+	}
+	r.render(result)
+}
+
+func (r *renderer) Error(err error) {
+	result := domain.NewErrorResult(err)
+	if r.config.Verbose {
+		result.Causes = domain.Causes(err)
+	}
+
+	if !r.Structured() {
+		PrintError(err.Error())
+		for _, cause := range result.Causes {
+			fmt.Printf("  caused by: %s\n", cause)
+		}
+		return
+	}
+	r.render(result)
+}
+
+func (r *renderer) render(v any) {
+	var out []byte
+	var err error
+	if r.config.Format == domain.YAMLOutput {
+		out, err = yaml.Marshal(v)
+	} else {
+		out, err = json.MarshalIndent(v, "", "  ")
+		out = append(out, '\n')
+	}
+	if err != nil {
+		PrintError(err.Error())
+		return
+	}
+	fmt.Print(string(out))
+}
@@ -0,0 +1,89 @@
+package util_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/sbonaiva/govm/internal/domain"
+	"github.com/sbonaiva/govm/internal/test"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	result := domain.UninstallResult{Platform: "linux/amd64", Versions: []string{"go1.22.3"}}
+	verboseErr := domain.NewUnexpectedError(domain.ErrCodeListVersions, &url.Error{Op: "Get", URL: "https://go.dev/dl/", Err: errors.New("connection refused")})
+
+	tests := []struct {
+		name     string
+		config   util.RenderConfig
+		render   func(renderer util.Renderer)
+		expected string
+	}{
+		{
+			name:     "Result As Text",
+			config:   util.RenderConfig{Format: domain.TextOutput},
+			render:   func(renderer util.Renderer) { renderer.Result(result, func() { util.PrintSuccess("Done") }) },
+			expected: "Done\n",
+		},
+		{
+			name:     "Result As JSON",
+			config:   util.RenderConfig{Format: domain.JSONOutput},
+			render:   func(renderer util.Renderer) { renderer.Result(result, func() { util.PrintSuccess("Done") }) },
+			expected: "{\n  \"platform\": \"linux/amd64\",\n  \"versions\": [\n    \"go1.22.3\"\n  ]\n}\n",
+		},
+		{
+			name:     "Result As YAML",
+			config:   util.RenderConfig{Format: domain.YAMLOutput},
+			render:   func(renderer util.Renderer) { renderer.Result(result, func() { util.PrintSuccess("Done") }) },
+			expected: "platform: linux/amd64\nversions:\n    - go1.22.3\n",
+		},
+		{
+			name:     "Error As Text",
+			config:   util.RenderConfig{Format: domain.TextOutput},
+			render:   func(renderer util.Renderer) { renderer.Error(domain.NewNoGoInstallationsFoundError()) },
+			expected: "Error: no go installations found Code: 103\n",
+		},
+		{
+			name:     "Error As JSON",
+			config:   util.RenderConfig{Format: domain.JSONOutput},
+			render:   func(renderer util.Renderer) { renderer.Error(domain.NewNoGoInstallationsFoundError()) },
+			expected: "{\n  \"error\": \"no go installations found\",\n  \"code\": 103\n}\n",
+		},
+		{
+			name:     "Error As YAML",
+			config:   util.RenderConfig{Format: domain.YAMLOutput},
+			render:   func(renderer util.Renderer) { renderer.Error(errors.New("boom")) },
+			expected: "error: boom\ncode: 0\n",
+		},
+		{
+			name:     "Verbose Error As Text",
+			config:   util.RenderConfig{Format: domain.TextOutput, Verbose: true},
+			render:   func(renderer util.Renderer) { renderer.Error(verboseErr) },
+			expected: "Error: could not get the release index: connection refused on https://go.dev/dl/ Code: 41\n  caused by: Get \"https://go.dev/dl/\": connection refused\n  caused by: connection refused\n",
+		},
+		{
+			name:     "Verbose Error As JSON",
+			config:   util.RenderConfig{Format: domain.JSONOutput, Verbose: true},
+			render:   func(renderer util.Renderer) { renderer.Error(verboseErr) },
+			expected: "{\n  \"error\": \"could not get the release index: connection refused on https://go.dev/dl/\",\n  \"code\": 41,\n  \"causes\": [\n    \"Get \\\"https://go.dev/dl/\\\": connection refused\",\n    \"connection refused\"\n  ]\n}\n",
+		},
+		{
+			name:     "Error As Text Without Verbose",
+			config:   util.RenderConfig{Format: domain.TextOutput},
+			render:   func(renderer util.Renderer) { renderer.Error(verboseErr) },
+			expected: "Error: could not get the release index: connection refused on https://go.dev/dl/ Code: 41\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, _ := test.CaptureOutput(func() error {
+				tt.render(util.NewRenderer(&tt.config))
+				return nil
+			})
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
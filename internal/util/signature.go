@@ -0,0 +1,59 @@
+package util
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// GoReleaseKey is the public key the Go release archives are signed with, the Google
+// Linux Packages Signing Authority key EB4C 1BFD 4F04 2F6D DDCC EC91 7721 F63B D38B 4796.
+//
+//go:embed go_release_key.asc
+var GoReleaseKey []byte
+
+// VerifySignature checks that signature, an armored detached OpenPGP signature, was
+// made over signed by one of the keys of the armored keyring key. Key expiry is
+// checked as of the time of signing, so archives signed before a subkey expired
+// remain valid.
+func VerifySignature(key []byte, signed io.Reader, signature []byte) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+	if err != nil {
+		return fmt.Errorf("reading keyring: %w", err)
+	}
+
+	block, err := armor.Decode(bytes.NewReader(signature))
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	if block.Type != openpgp.SignatureType {
+		return fmt.Errorf("unexpected armor type %q", block.Type)
+	}
+
+	body, err := io.ReadAll(block.Body)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+
+	p, err := packet.Read(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("reading signature: %w", err)
+	}
+	sig, ok := p.(*packet.Signature)
+	if !ok {
+		return fmt.Errorf("unexpected packet %T in signature", p)
+	}
+
+	config := &packet.Config{Time: func() time.Time { return sig.CreationTime }}
+	if _, err := openpgp.CheckDetachedSignature(keyring, signed, bytes.NewReader(body), config); err != nil {
+		return fmt.Errorf("checking signature: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package util_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/sbonaiva/govm/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Now()
+	signer := newEntity(t, now.Add(-3*time.Hour))
+	signature := sign(t, signer, "archive", now.Add(-150*time.Minute))
+	lateSignature := sign(t, signer, "archive", now.Add(-time.Hour))
+	key := armoredKey(t, signer, time.Hour)
+	otherKey := armoredKey(t, newEntity(t, now.Add(-3*time.Hour)), 0)
+
+	tests := []struct {
+		name      string
+		key       []byte
+		signed    string
+		signature []byte
+		expected  string
+	}{
+		{
+			name:      "Valid",
+			key:       key,
+			signed:    "archive",
+			signature: signature,
+		},
+		{
+			name:      "Tampered Content",
+			key:       key,
+			signed:    "tampered",
+			signature: signature,
+			expected:  "checking signature",
+		},
+		{
+			name:      "Unknown Key",
+			key:       otherKey,
+			signed:    "archive",
+			signature: signature,
+			expected:  "checking signature",
+		},
+		{
+			name:      "Signed After Key Expired",
+			key:       key,
+			signed:    "archive",
+			signature: lateSignature,
+			expected:  "checking signature",
+		},
+		{
+			name:      "Invalid Signature",
+			key:       key,
+			signed:    "archive",
+			signature: []byte("xpto"),
+			expected:  "decoding signature",
+		},
+		{
+			name:      "Invalid Key",
+			key:       []byte("xpto"),
+			signed:    "archive",
+			signature: signature,
+			expected:  "reading keyring",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := util.VerifySignature(tt.key, strings.NewReader(tt.signed), tt.signature)
+
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGoReleaseKey(t *testing.T) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(util.GoReleaseKey))
+
+	assert.NoError(t, err)
+	assert.Len(t, keyring, 1)
+	assert.Equal(t, "EB4C1BFD4F042F6DDDCCEC917721F63BD38B4796", fmt.Sprintf("%X", keyring[0].PrimaryKey.Fingerprint))
+}
+
+func newEntity(t *testing.T, created time.Time) *openpgp.Entity {
+	config := &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA, Time: func() time.Time { return created }}
+	entity, err := openpgp.NewEntity("govm", "", "govm@example.com", config)
+	assert.NoError(t, err)
+	return entity
+}
+
+// armoredKey exports the public key of entity, expiring after lifetime unless zero.
+// Once it expires entity can no longer sign, so sign with it beforehand.
+func armoredKey(t *testing.T, entity *openpgp.Entity, lifetime time.Duration) []byte {
+	if lifetime > 0 {
+		for _, identity := range entity.Identities {
+			secs := uint32(lifetime.Seconds())
+			identity.SelfSignature.KeyLifetimeSecs = &secs
+			assert.NoError(t, identity.SelfSignature.SignUserId(identity.UserId.Id, entity.PrimaryKey, entity.PrivateKey, nil))
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(w))
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func sign(t *testing.T, signer *openpgp.Entity, content string, at time.Time) []byte {
+	var buf bytes.Buffer
+	config := &packet.Config{Time: func() time.Time { return at }}
+	assert.NoError(t, openpgp.ArmoredDetachSign(&buf, signer, strings.NewReader(content), config))
+	return buf.Bytes()
+}